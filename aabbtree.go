@@ -0,0 +1,368 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// AABBTree is a dynamic bounding volume hierarchy of Rects and Circles, each identified by
+// an index chosen by the caller. Each shape is stored with a fat bounding Rect, grown by a
+// margin, so that shapes which move a little don't require the tree to be updated. Unlike a
+// Quadtree or SpatialHash it needs no bounds or cell size, and it keeps itself balanced
+// however the shapes are spread out or sized.
+type AABBTree struct {
+	root   *aabbNode
+	leaves map[int]*aabbNode
+	margin float64
+}
+
+// aabbNode is either a leaf, which holds a shape, or a branch, which has two children.
+type aabbNode struct {
+	// bounds surrounds both children of a branch, or is the fat bounds of a leaf.
+	bounds              Rect
+	parent, left, right *aabbNode
+	// height is 0 for leaves.
+	height int
+	i      int
+	shape  shape
+}
+
+func (n *aabbNode) isLeaf() bool {
+	return n.left == nil
+}
+
+// NewAABBTree creates an empty AABBTree. Each shape's bounds are grown by margin on every
+// side.
+func NewAABBTree(margin float64) *AABBTree {
+	return &AABBTree{
+		leaves: make(map[int]*aabbNode),
+		margin: margin,
+	}
+}
+
+// Len returns the number of shapes in the AABBTree.
+func (t *AABBTree) Len() int {
+	return len(t.leaves)
+}
+
+// InsertRect adds the Rect to the AABBTree with the index i. If i is already in the
+// AABBTree then it is moved instead.
+func (t *AABBTree) InsertRect(i int, r Rect) {
+	t.move(i, rectShape(r))
+}
+
+// InsertCircle adds the Circle to the AABBTree with the index i. If i is already in the
+// AABBTree then it is moved instead.
+func (t *AABBTree) InsertCircle(i int, c Circle) {
+	t.move(i, circleShape(c))
+}
+
+// MoveRect changes the shape with index i to the Rect. If i isn't in the AABBTree then it
+// is inserted. The tree only changes if the Rect leaves its fat bounds.
+func (t *AABBTree) MoveRect(i int, r Rect) {
+	t.move(i, rectShape(r))
+}
+
+// MoveCircle changes the shape with index i to the Circle. If i isn't in the AABBTree then
+// it is inserted. The tree only changes if the Circle leaves its fat bounds.
+func (t *AABBTree) MoveCircle(i int, c Circle) {
+	t.move(i, circleShape(c))
+}
+
+func (t *AABBTree) move(i int, s shape) {
+	leaf, ok := t.leaves[i]
+	if ok {
+		leaf.shape = s
+		if leaf.bounds.Contains(s.bounds()) {
+			return
+		}
+		t.removeLeaf(leaf)
+	} else {
+		leaf = &aabbNode{i: i, shape: s}
+		t.leaves[i] = leaf
+	}
+	leaf.bounds = s.bounds().Inflated(2*t.margin, 2*t.margin)
+	t.insertLeaf(leaf)
+}
+
+// Remove removes the shape with index i and returns true, or returns false if i isn't in
+// the AABBTree.
+func (t *AABBTree) Remove(i int) bool {
+	leaf, ok := t.leaves[i]
+	if !ok {
+		return false
+	}
+	delete(t.leaves, i)
+	t.removeLeaf(leaf)
+	return true
+}
+
+// QueryRect returns a sorted list of indices of the shapes that collide with the Rect, or
+// an empty list if none.
+func (t *AABBTree) QueryRect(r Rect) []int {
+	r.Normalize()
+	return t.query(
+		func(n *aabbNode) bool { return r.CollideRect(n.bounds) },
+		func(s shape) bool { return s.collideRect(r) })
+}
+
+// QueryCircle returns a sorted list of indices of the shapes that collide with the Circle,
+// or an empty list if none.
+func (t *AABBTree) QueryCircle(c Circle) []int {
+	return t.query(
+		func(n *aabbNode) bool { return c.CollideRect(n.bounds) },
+		func(s shape) bool { return s.collideCircle(c) })
+}
+
+// QueryPoint returns a sorted list of indices of the shapes that contain the point, or an
+// empty list if none.
+func (t *AABBTree) QueryPoint(x, y float64) []int {
+	return t.query(
+		func(n *aabbNode) bool { return n.bounds.CollidePoint(x, y) },
+		func(s shape) bool { return s.collidePoint(x, y) })
+}
+
+// QueryRay returns a sorted list of indices of the shapes that the Ray intersects in front
+// of its origin, including the shapes that contain the origin, or an empty list if none.
+func (t *AABBTree) QueryRay(r Ray) []int {
+	return t.query(
+		func(n *aabbNode) bool {
+			_, tMax, hit := r.IntersectRect(n.bounds)
+			return hit && tMax >= 0
+		},
+		func(s shape) bool {
+			_, _, hit := s.intersectRay(r)
+			return hit
+		})
+}
+
+// RayCast returns the index of the first shape the Ray hits in front of its origin, along
+// with the distance dist from the origin to the hit. If the origin is inside of a shape then
+// dist is 0. Ties go to the lowest index. If the Ray doesn't hit anything then ok is false
+// and i and dist are undefined.
+func (t *AABBTree) RayCast(r Ray) (i int, dist float64, ok bool) {
+	dist = math.Inf(1)
+	if t.root == nil {
+		return
+	}
+	stack := []*aabbNode{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n.isLeaf() {
+			tMin, _, hit := n.shape.intersectRay(r)
+			if tMin = math.Max(tMin, 0); hit && (tMin < dist || tMin == dist && n.i < i) {
+				i, dist, ok = n.i, tMin, true
+			}
+			continue
+		}
+		for _, child := range []*aabbNode{n.left, n.right} {
+			// Skip branches that can't contain anything closer than the current hit.
+			if tMin, tMax, hit := r.IntersectRect(child.bounds); hit && tMax >= 0 && tMin <= dist {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return
+}
+
+// Pairs returns every pair of shapes in the AABBTree that collide, each pair exactly once.
+// The pairs are sorted by A and then B.
+func (t *AABBTree) Pairs() []Pair {
+	pairs := make([]Pair, 0)
+	for _, a := range t.leaves {
+		bounds := a.shape.bounds()
+		t.visit(
+			func(n *aabbNode) bool { return bounds.CollideRect(n.bounds) },
+			func(b *aabbNode) {
+				if a.i < b.i && a.shape.collideShape(b.shape) {
+					pairs = append(pairs, Pair{A: a.i, B: b.i})
+				}
+			})
+	}
+	sortPairs(pairs)
+	return pairs
+}
+
+func (t *AABBTree) query(visit func(*aabbNode) bool, match func(shape) bool) []int {
+	list := make([]int, 0)
+	t.visit(visit, func(leaf *aabbNode) {
+		if match(leaf.shape) {
+			list = append(list, leaf.i)
+		}
+	})
+	sort.Ints(list)
+	return list
+}
+
+// visit calls fn for each leaf, only descending into nodes for which visit returns true.
+func (t *AABBTree) visit(visit func(*aabbNode) bool, fn func(*aabbNode)) {
+	if t.root == nil {
+		return
+	}
+	stack := []*aabbNode{t.root}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !visit(n) {
+			continue
+		}
+		if n.isLeaf() {
+			fn(n)
+		} else {
+			stack = append(stack, n.left, n.right)
+		}
+	}
+}
+
+// perimeter is used as the cost of a node's bounds when choosing where to insert.
+func perimeter(r Rect) float64 {
+	return 2 * (r.W + r.H)
+}
+
+func (t *AABBTree) insertLeaf(leaf *aabbNode) {
+	// Based on Box2D's b2DynamicTree.
+	// https://github.com/erincatto/box2d/blob/v2.3.1/Box2D/Box2D/Collision/b2DynamicTree.cpp
+	leaf.parent = nil
+	if t.root == nil {
+		t.root = leaf
+		return
+	}
+
+	// Find the best sibling for the new leaf.
+	sibling := t.root
+	for !sibling.isLeaf() {
+		area := perimeter(sibling.bounds)
+		combined := perimeter(sibling.bounds.Unioned(leaf.bounds))
+		// Cost of creating a new parent for this node and the new leaf.
+		cost := 2 * combined
+		// Minimum cost of pushing the leaf further down the tree.
+		inheritance := 2 * (combined - area)
+
+		childCost := func(child *aabbNode) float64 {
+			c := perimeter(leaf.bounds.Unioned(child.bounds)) + inheritance
+			if !child.isLeaf() {
+				c -= perimeter(child.bounds)
+			}
+			return c
+		}
+		cost1 := childCost(sibling.left)
+		cost2 := childCost(sibling.right)
+		if cost < cost1 && cost < cost2 {
+			break
+		}
+		if cost1 < cost2 {
+			sibling = sibling.left
+		} else {
+			sibling = sibling.right
+		}
+	}
+
+	oldParent := sibling.parent
+	newParent := &aabbNode{
+		bounds: leaf.bounds.Unioned(sibling.bounds),
+		parent: oldParent,
+		left:   sibling,
+		right:  leaf,
+		height: sibling.height + 1,
+	}
+	sibling.parent = newParent
+	leaf.parent = newParent
+	t.replaceChild(oldParent, sibling, newParent)
+	t.refit(newParent.parent)
+}
+
+func (t *AABBTree) removeLeaf(leaf *aabbNode) {
+	if leaf == t.root {
+		t.root = nil
+		return
+	}
+	parent := leaf.parent
+	sibling := parent.left
+	if sibling == leaf {
+		sibling = parent.right
+	}
+	sibling.parent = parent.parent
+	t.replaceChild(parent.parent, parent, sibling)
+	t.refit(sibling.parent)
+	leaf.parent = nil
+}
+
+// replaceChild makes newChild take the place of oldChild in parent, or become the root if
+// parent is nil.
+func (t *AABBTree) replaceChild(parent, oldChild, newChild *aabbNode) {
+	switch {
+	case parent == nil:
+		t.root = newChild
+	case parent.left == oldChild:
+		parent.left = newChild
+	default:
+		parent.right = newChild
+	}
+}
+
+// refit walks up from n to the root, rebalancing and updating the bounds and height of each
+// node along the way.
+func (t *AABBTree) refit(n *aabbNode) {
+	for n != nil {
+		n = t.balance(n)
+		n.height = 1 + maxInt(n.left.height, n.right.height)
+		n.bounds = n.left.bounds.Unioned(n.right.bounds)
+		n = n.parent
+	}
+}
+
+// balance performs a left or right rotation if a is unbalanced and returns the node that
+// takes a's place.
+func (t *AABBTree) balance(a *aabbNode) *aabbNode {
+	if a.isLeaf() || a.height < 2 {
+		return a
+	}
+	b, c := a.left, a.right
+	diff := c.height - b.height
+	switch {
+	case diff > 1:
+		// Rotate c up.
+		f, g := c.left, c.right
+		c.left = a
+		c.parent = a.parent
+		a.parent = c
+		t.replaceChild(c.parent, a, c)
+		if f.height > g.height {
+			c.right = f
+			a.right = g
+			g.parent = a
+		} else {
+			c.right = g
+			a.right = f
+			f.parent = a
+		}
+		a.bounds = b.bounds.Unioned(a.right.bounds)
+		a.height = 1 + maxInt(b.height, a.right.height)
+		c.bounds = a.bounds.Unioned(c.right.bounds)
+		c.height = 1 + maxInt(a.height, c.right.height)
+		return c
+	case diff < -1:
+		// Rotate b up.
+		d, e := b.left, b.right
+		b.left = a
+		b.parent = a.parent
+		a.parent = b
+		t.replaceChild(b.parent, a, b)
+		if d.height > e.height {
+			b.right = d
+			a.left = e
+			e.parent = a
+		} else {
+			b.right = e
+			a.left = d
+			d.parent = a
+		}
+		a.bounds = c.bounds.Unioned(a.left.bounds)
+		a.height = 1 + maxInt(c.height, a.left.height)
+		b.bounds = a.bounds.Unioned(b.right.bounds)
+		b.height = 1 + maxInt(a.height, b.right.height)
+		return b
+	}
+	return a
+}
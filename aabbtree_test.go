@@ -0,0 +1,227 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// checkAABBTree checks that every branch surrounds its children, is balanced, and has the
+// right height and parent links.
+func checkAABBTree(t *testing.T, stage string, tree *AABBTree) {
+	leaves := 0
+	var check func(n *aabbNode) int
+	check = func(n *aabbNode) int {
+		if n.isLeaf() {
+			leaves++
+			if tree.leaves[n.i] != n {
+				t.Errorf("%s: leaf %d not in map", stage, n.i)
+			}
+			if !n.bounds.Contains(n.shape.bounds()) {
+				t.Errorf("%s: leaf %d: bounds %s don't contain %s", stage, n.i, n.bounds, n.shape.bounds())
+			}
+			return 0
+		}
+		if n.left.parent != n || n.right.parent != n {
+			t.Errorf("%s: bad parent link", stage)
+		}
+		// Unioned can be off by rounding when computing the right and bottom edges.
+		if bounds := n.bounds.Inflated(e, e); !bounds.Contains(n.left.bounds) || !bounds.Contains(n.right.bounds) {
+			t.Errorf("%s: branch bounds %s don't contain children", stage, n.bounds)
+		}
+		hl, hr := check(n.left), check(n.right)
+		if hl-hr > 1 || hr-hl > 1 {
+			t.Errorf("%s: unbalanced: heights %d, %d", stage, hl, hr)
+		}
+		if h := 1 + maxInt(hl, hr); n.height != h {
+			t.Errorf("%s: height: got %d, want %d", stage, n.height, h)
+		}
+		return n.height
+	}
+	if tree.root != nil {
+		if tree.root.parent != nil {
+			t.Errorf("%s: root has parent", stage)
+		}
+		check(tree.root)
+	}
+	if leaves != tree.Len() {
+		t.Errorf("%s: leaves: got %d, want %d", stage, leaves, tree.Len())
+	}
+}
+
+func TestAABBTreeRects(t *testing.T) {
+	bounds := RectXYWH(-100, -100, 200, 200)
+	tree := NewAABBTree(2)
+	rects := make([]Rect, 200)
+	for i := range rects {
+		rects[i] = randRect(bounds, 15)
+		tree.InsertRect(i, rects[i])
+	}
+	if tree.Len() != len(rects) {
+		t.Errorf("len: got %d, want %d", tree.Len(), len(rects))
+	}
+
+	checkAABBTree(t, "inserted", tree)
+	checkRectPairs(t, "inserted", tree.Pairs(), rects)
+	checkRectQueries(t, "inserted", tree, rects, bounds)
+
+	for frame := 0; frame < 5; frame++ {
+		for i := range rects {
+			rects[i].Move(rand.Float64()*6-3, rand.Float64()*6-3)
+			tree.MoveRect(i, rects[i])
+		}
+		checkAABBTree(t, "moved", tree)
+		checkRectPairs(t, "moved", tree.Pairs(), rects)
+		checkRectQueries(t, "moved", tree, rects, bounds)
+	}
+
+	removeRects(t, tree, rects)
+	checkAABBTree(t, "removed", tree)
+	checkRectPairs(t, "removed", tree.Pairs(), rects)
+	checkRectQueries(t, "removed", tree, rects, bounds)
+
+	for i := range rects {
+		tree.Remove(i)
+	}
+	if tree.Len() != 0 || tree.root != nil {
+		t.Errorf("empty: got len %d, root %v", tree.Len(), tree.root)
+	}
+}
+
+func TestAABBTreeCircles(t *testing.T) {
+	bounds := RectXYWH(-100, -100, 200, 200)
+	tree := NewAABBTree(1)
+	circles := make([]Circle, 200)
+	for i := range circles {
+		circles[i] = randCircle(bounds, 8)
+		tree.InsertCircle(i, circles[i])
+	}
+	checkAABBTree(t, "inserted", tree)
+
+	wantPairs := []Pair{}
+	for i, c := range circles {
+		for j := i + 1; j < len(circles); j++ {
+			if c.CollideCircle(circles[j]) {
+				wantPairs = append(wantPairs, Pair{A: i, B: j})
+			}
+		}
+	}
+	if got := tree.Pairs(); !pairListEqual(got, wantPairs) {
+		t.Errorf("pairs: got %v, want %v", got, wantPairs)
+	}
+
+	for i := range circles {
+		circles[i].Move(rand.Float64()*10-5, rand.Float64()*10-5)
+		tree.MoveCircle(i, circles[i])
+	}
+	checkAABBTree(t, "moved", tree)
+	for trial := 0; trial < 50; trial++ {
+		r := randRect(bounds, 50)
+		if got, want := tree.QueryRect(r), r.CollideCircleListAll(circles); !intListEqual(got, want) {
+			t.Errorf("rect %s: got %v, want %v", r, got, want)
+		}
+		c := randCircle(bounds, 30)
+		if got, want := tree.QueryCircle(c), c.CollideCircleListAll(circles); !intListEqual(got, want) {
+			t.Errorf("circle %s: got %v, want %v", c, got, want)
+		}
+	}
+}
+
+func TestAABBTreeMoveWithinMargin(t *testing.T) {
+	tree := NewAABBTree(2)
+	tree.InsertRect(0, RectXYWH(0, 0, 10, 10))
+	tree.InsertRect(1, RectXYWH(50, 50, 10, 10))
+	leaf := tree.leaves[0]
+	fat := leaf.bounds
+
+	// Within the margin the fat bounds stay the same, but queries use the new Rect.
+	tree.MoveRect(0, RectXYWH(1.5, -1.5, 10, 10))
+	if tree.leaves[0] != leaf || leaf.bounds != fat {
+		t.Errorf("small move: got bounds %s, want %s", leaf.bounds, fat)
+	}
+	if got := tree.QueryPoint(11, 5); !intListEqual(got, []int{0}) {
+		t.Errorf("small move: got %v, want %v", got, []int{0})
+	}
+
+	tree.MoveRect(0, RectXYWH(3, 0, 10, 10))
+	if want := RectXYWH(1, -2, 14, 14); leaf.bounds != want {
+		t.Errorf("large move: got bounds %s, want %s", leaf.bounds, want)
+	}
+	checkAABBTree(t, "large move", tree)
+}
+
+func TestAABBTreeRayCast(t *testing.T) {
+	tree := NewAABBTree(1)
+	tree.InsertRect(0, RectXYWH(10, 10, 5, 5))
+	tree.InsertRect(1, RectXYWH(80, 11, 5, 5))
+	tree.InsertCircle(2, CircleXYR(50, 12, 3))
+	tree.InsertCircle(3, CircleXYR(50, 80, 3))
+	tree.InsertRect(4, RectXYWH(0, 5, 3, 10))
+
+	cases := []struct {
+		r     Ray
+		i     int
+		dist  float64
+		ok    bool
+		query []int
+	}{
+		{Ray{VecXY(5, 12), VecXY(1, 0)}, 0, 5, true, []int{0, 1, 2}},
+		{Ray{VecXY(60, 12), VecXY(1, 0)}, 1, 20, true, []int{1}},
+		{Ray{VecXY(60, 12), VecXY(-1, 0)}, 2, 7, true, []int{0, 2, 4}},
+		{Ray{VecXY(12, 12), VecXY(1, 0)}, 0, 0, true, []int{0, 1, 2}},
+		{Ray{VecXY(50, 0), VecXY(0, 2)}, 2, 9, true, []int{2, 3}},
+		{Ray{VecXY(50, 100), VecXY(0, -1)}, 3, 17, true, []int{2, 3}},
+		{Ray{VecXY(50, 0), VecXY(0, -1)}, 0, 0, false, []int{}},
+		{Ray{VecXY(-10, 50), VecXY(1, 0)}, 0, 0, false, []int{}},
+	}
+
+	for i, c := range cases {
+		gotI, gotDist, gotOk := tree.RayCast(c.r)
+		if gotOk != c.ok || c.ok && (gotI != c.i || math.Abs(gotDist-c.dist) > e) {
+			t.Errorf("case %d: got %d, %f, %t, want %d, %f, %t", i, gotI, gotDist, gotOk, c.i, c.dist, c.ok)
+		}
+		if got := tree.QueryRay(c.r); !intListEqual(got, c.query) {
+			t.Errorf("case %d: query: got %v, want %v", i, got, c.query)
+		}
+	}
+
+	if _, _, ok := NewAABBTree(1).RayCast(Ray{VecXY(0, 0), VecXY(1, 0)}); ok {
+		t.Errorf("empty: got true, want false")
+	}
+}
+
+func TestAABBTreeRayCastRandom(t *testing.T) {
+	bounds := RectXYWH(-100, -100, 200, 200)
+	tree := NewAABBTree(2)
+	shapes := make([]shape, 100)
+	for i := range shapes {
+		if i%2 == 0 {
+			r := randRect(bounds, 15)
+			shapes[i] = rectShape(r)
+			tree.InsertRect(i, r)
+		} else {
+			c := randCircle(bounds, 8)
+			shapes[i] = circleShape(c)
+			tree.InsertCircle(i, c)
+		}
+	}
+
+	for trial := 0; trial < 100; trial++ {
+		r := Ray{
+			Origin:    VecXY(rand.Float64()*300-150, rand.Float64()*300-150),
+			Direction: VecXY(rand.Float64()*2-1, rand.Float64()*2-1),
+		}
+		wantI, wantDist, wantOk := 0, math.Inf(1), false
+		for i, s := range shapes {
+			tMin, _, hit := s.intersectRay(r)
+			if tMin = math.Max(tMin, 0); hit && tMin < wantDist {
+				// Shapes are checked in order, so ties already go to the lowest index.
+				wantI, wantDist, wantOk = i, tMin, true
+			}
+		}
+		gotI, gotDist, gotOk := tree.RayCast(r)
+		if gotOk != wantOk || wantOk && (gotI != wantI || math.Abs(gotDist-wantDist) > e) {
+			t.Errorf("ray %s: got %d, %f, %t, want %d, %f, %t", r, gotI, gotDist, gotOk, wantI, wantDist, wantOk)
+		}
+	}
+}
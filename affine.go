@@ -0,0 +1,225 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// Affine is a 2-D affine transformation. It represents the matrix
+//  | A C E |
+//  | B D F |
+//  | 0 0 1 |
+// so that a point (x, y) is transformed to (A*x + C*y + E, B*x + D*y + F). Like Vec.Rotate,
+// positive rotations are counterclockwise in screen coordinates. The zero value is not the
+// identity, use AffineIdentity instead.
+type Affine struct {
+	A, B, C, D, E, F float64
+}
+
+// AffineIdentity is the transform that leaves everything unchanged.
+var AffineIdentity = Affine{A: 1, D: 1}
+
+func (a Affine) String() string {
+	return fmt.Sprintf("Affine(%g, %g, %g, %g, %g, %g)", a.A, a.B, a.C, a.D, a.E, a.F)
+}
+
+// AffineTranslate creates an Affine that moves by (dx, dy).
+func AffineTranslate(dx, dy float64) Affine {
+	return Affine{A: 1, D: 1, E: dx, F: dy}
+}
+
+// AffineRotate creates an Affine that rotates (counterclockwise in screen coordinates) by
+// the given radians around the origin.
+func AffineRotate(rad float64) Affine {
+	sin, cos := math.Sincos(rad)
+	return Affine{A: cos, B: -sin, C: sin, D: cos}
+}
+
+// AffineScale creates an Affine that scales by sx horizontally and sy vertically, relative
+// to the origin.
+func AffineScale(sx, sy float64) Affine {
+	return Affine{A: sx, D: sy}
+}
+
+// AffineShear creates an Affine that shears by shx horizontally and shy vertically. A point
+// (x, y) is transformed to (x + shx*y, y + shy*x).
+func AffineShear(shx, shy float64) Affine {
+	return Affine{A: 1, B: shy, C: shx, D: 1}
+}
+
+// AffineCompose creates an Affine that applies, in order, a scale, a horizontal shear, a
+// rotation, and then a translation. It is the inverse of Affine.Decompose.
+func AffineCompose(translate Vec, rad float64, scale Vec, shear float64) Affine {
+	return AffineScale(scale.X, scale.Y).
+		Sheared(shear, 0).
+		Rotated(rad).
+		Translated(translate.X, translate.Y)
+}
+
+// Equals returns true if the corresponding components of the transforms are within the
+// error e.
+func (a Affine) Equals(other Affine, e float64) bool {
+	return math.Abs(a.A-other.A) < e && math.Abs(a.B-other.B) < e &&
+		math.Abs(a.C-other.C) < e && math.Abs(a.D-other.D) < e &&
+		math.Abs(a.E-other.E) < e && math.Abs(a.F-other.F) < e
+}
+
+// Mul modifies a to be the matrix product a * other. The resulting transform applies other
+// first and then a.
+func (a *Affine) Mul(other Affine) {
+	*a = a.Times(other)
+}
+
+// Times returns the matrix product a * other. The returned transform applies other first
+// and then a.
+func (a Affine) Times(other Affine) Affine {
+	return Affine{
+		A: a.A*other.A + a.C*other.B,
+		B: a.B*other.A + a.D*other.B,
+		C: a.A*other.C + a.C*other.D,
+		D: a.B*other.C + a.D*other.D,
+		E: a.A*other.E + a.C*other.F + a.E,
+		F: a.B*other.E + a.D*other.F + a.F,
+	}
+}
+
+// Translate modifies a so that it also moves by (dx, dy) after its current transformation.
+func (a *Affine) Translate(dx, dy float64) {
+	*a = AffineTranslate(dx, dy).Times(*a)
+}
+
+// Translated returns a new Affine that applies a and then moves by (dx, dy).
+func (a Affine) Translated(dx, dy float64) Affine {
+	return AffineTranslate(dx, dy).Times(a)
+}
+
+// Rotate modifies a so that it also rotates around the origin after its current
+// transformation.
+func (a *Affine) Rotate(rad float64) {
+	*a = AffineRotate(rad).Times(*a)
+}
+
+// Rotated returns a new Affine that applies a and then rotates around the origin.
+func (a Affine) Rotated(rad float64) Affine {
+	return AffineRotate(rad).Times(a)
+}
+
+// Scale modifies a so that it also scales relative to the origin after its current
+// transformation.
+func (a *Affine) Scale(sx, sy float64) {
+	*a = AffineScale(sx, sy).Times(*a)
+}
+
+// Scaled returns a new Affine that applies a and then scales relative to the origin.
+func (a Affine) Scaled(sx, sy float64) Affine {
+	return AffineScale(sx, sy).Times(a)
+}
+
+// Shear modifies a so that it also shears after its current transformation.
+func (a *Affine) Shear(shx, shy float64) {
+	*a = AffineShear(shx, shy).Times(*a)
+}
+
+// Sheared returns a new Affine that applies a and then shears.
+func (a Affine) Sheared(shx, shy float64) Affine {
+	return AffineShear(shx, shy).Times(a)
+}
+
+// Det returns the determinant of the transform. A determinant of 0 means the transform
+// can't be inverted and a negative determinant means it mirrors.
+func (a Affine) Det() float64 {
+	return a.A*a.D - a.B*a.C
+}
+
+// Invert modifies a to be its inverse. If a can't be inverted then it is left unchanged and
+// false is returned.
+func (a *Affine) Invert() bool {
+	inv, ok := a.Inverse()
+	if ok {
+		*a = inv
+	}
+	return ok
+}
+
+// Inverse returns the transform that undoes a. If a can't be inverted then ok is false and
+// inv is undefined.
+func (a Affine) Inverse() (inv Affine, ok bool) {
+	det := a.Det()
+	if det == 0 {
+		return
+	}
+	return Affine{
+		A: a.D / det,
+		B: -a.B / det,
+		C: -a.C / det,
+		D: a.A / det,
+		E: (a.C*a.F - a.D*a.E) / det,
+		F: (a.B*a.E - a.A*a.F) / det,
+	}, true
+}
+
+// Decompose splits the transform into the parameters for AffineCompose, such that
+// AffineCompose(a.Decompose()) is equivalent to a. A mirrored transform results in a
+// negative scale.Y. If the transform collapses the x-axis (scale.X is 0) then rad and shear
+// are 0 and scale.Y is undefined.
+func (a Affine) Decompose() (translate Vec, rad float64, scale Vec, shear float64) {
+	translate = Vec{X: a.E, Y: a.F}
+	scale.X = math.Hypot(a.A, a.B)
+	if scale.X == 0 {
+		scale.Y = math.Hypot(a.C, a.D)
+		return
+	}
+	rad = -math.Atan2(a.B, a.A)
+	sin, cos := math.Sincos(rad)
+	// Undo the rotation on the second column to leave the shear and vertical scale.
+	scale.Y = sin*a.C + cos*a.D
+	if scale.Y != 0 {
+		shear = (cos*a.C - sin*a.D) / scale.Y
+	}
+	return
+}
+
+// maxScale returns the largest factor by which the transform stretches any vector.
+func (a Affine) maxScale() float64 {
+	s := a.A*a.A + a.B*a.B + a.C*a.C + a.D*a.D
+	det := a.Det()
+	return math.Sqrt((s + math.Sqrt(math.Max(s*s-4*det*det, 0))) / 2)
+}
+
+// ApplyVec returns the result of transforming the point v.
+func (a Affine) ApplyVec(v Vec) Vec {
+	return Vec{X: a.A*v.X + a.C*v.Y + a.E, Y: a.B*v.X + a.D*v.Y + a.F}
+}
+
+// ApplyVecLinear returns the result of transforming the direction v, which ignores the
+// translation.
+func (a Affine) ApplyVecLinear(v Vec) Vec {
+	return Vec{X: a.A*v.X + a.C*v.Y, Y: a.B*v.X + a.D*v.Y}
+}
+
+// ApplyRay returns the result of transforming the Ray. The origin is transformed as a point
+// and the direction without the translation.
+func (a Affine) ApplyRay(r Ray) Ray {
+	return Ray{Origin: a.ApplyVec(r.Origin), Direction: a.ApplyVecLinear(r.Direction)}
+}
+
+// ApplyRect returns the smallest Rect that surrounds the transformed Rect.
+func (a Affine) ApplyRect(r Rect) Rect {
+	return a.ApplyPolygon(PolygonRect(r)).BoundingRect()
+}
+
+// ApplyCircle returns the smallest Circle that surrounds the transformed Circle. The
+// transformed shape is only a circle if the transform scales uniformly, otherwise it is
+// an ellipse and the returned Circle is centered on it.
+func (a Affine) ApplyCircle(c Circle) Circle {
+	return CircleVecR(a.ApplyVec(c.Pos()), math.Abs(c.R)*a.maxScale())
+}
+
+// ApplyPolygon returns a new Polygon with each vertex transformed.
+func (a Affine) ApplyPolygon(p Polygon) Polygon {
+	transformed := make(Polygon, len(p))
+	for i, v := range p {
+		transformed[i] = a.ApplyVec(v)
+	}
+	return transformed
+}
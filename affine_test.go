@@ -0,0 +1,148 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestAffineString(t *testing.T) {
+	a := Affine{A: 1, B: -2.5, C: 3, D: 4, E: 5.5, F: -6}
+	got := a.String()
+	want := "Affine(1, -2.5, 3, 4, 5.5, -6)"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestAffineApplyVec(t *testing.T) {
+	cases := []struct {
+		a    Affine
+		v    Vec
+		want Vec
+	}{
+		{AffineIdentity, VecXY(1, 2), VecXY(1, 2)},
+		{AffineTranslate(3, -4), VecXY(1, 2), VecXY(4, -2)},
+		{AffineRotate(math.Pi / 2), VecXY(1, 0), VecXY(0, -1)},
+		{AffineRotate(math.Pi / 2), VecXY(0, 1), VecXY(1, 0)},
+		{AffineScale(2, 3), VecXY(1, 2), VecXY(2, 6)},
+		{AffineShear(2, 0), VecXY(1, 2), VecXY(5, 2)},
+		{AffineShear(0, 2), VecXY(1, 2), VecXY(1, 4)},
+		{AffineTranslate(1, 1).Times(AffineScale(2, 2)), VecXY(1, 2), VecXY(3, 5)},
+		{AffineScale(2, 2).Translated(1, 1), VecXY(1, 2), VecXY(3, 5)},
+		{AffineTranslate(1, 1).Scaled(2, 2), VecXY(1, 2), VecXY(4, 6)},
+	}
+
+	for i, c := range cases {
+		got := c.a.ApplyVec(c.v)
+		if !got.Equals(c.want, e) {
+			t.Errorf("case %d: got %s, want %s", i, got, c.want)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		v := VecXY(rand.Float64()*100-50, rand.Float64()*100-50)
+		rad := rand.Float64() * 2 * math.Pi
+		got := AffineRotate(rad).ApplyVec(v)
+		want := v.Rotated(rad)
+		if !got.Equals(want, 1e-9) {
+			t.Errorf("rotate %f: got %s, want %s", rad, got, want)
+		}
+	}
+}
+
+func TestAffineInPlace(t *testing.T) {
+	a := AffineIdentity
+	a.Scale(2, 3)
+	a.Shear(1, 0)
+	a.Rotate(1)
+	a.Translate(4, 5)
+	a.Mul(AffineTranslate(-1, 2))
+	want := AffineScale(2, 3).Sheared(1, 0).Rotated(1).Translated(4, 5).Times(AffineTranslate(-1, 2))
+	if !a.Equals(want, e) {
+		t.Errorf("got %s, want %s", a, want)
+	}
+}
+
+func TestAffineInverse(t *testing.T) {
+	a := AffineCompose(VecXY(3, -2), 0.7, VecXY(2, -0.5), 0.3)
+	inv, ok := a.Inverse()
+	if !ok {
+		t.Fatalf("%s should be invertible", a)
+	}
+	if got := a.Times(inv); !got.Equals(AffineIdentity, e) {
+		t.Errorf("a * inv: got %s, want %s", got, AffineIdentity)
+	}
+	if got := inv.Times(a); !got.Equals(AffineIdentity, e) {
+		t.Errorf("inv * a: got %s, want %s", got, AffineIdentity)
+	}
+
+	b := a
+	if !b.Invert() || !b.Equals(inv, e) {
+		t.Errorf("invert: got %s, want %s", b, inv)
+	}
+
+	singular := AffineScale(0, 1).Translated(1, 2)
+	if _, ok := singular.Inverse(); ok {
+		t.Errorf("%s should not be invertible", singular)
+	}
+	b = singular
+	if b.Invert() || b != singular {
+		t.Errorf("invert singular: got %s, want %s", b, singular)
+	}
+}
+
+func TestAffineDecompose(t *testing.T) {
+	cases := []struct {
+		translate Vec
+		rad       float64
+		scale     Vec
+		shear     float64
+	}{
+		{VecXY(0, 0), 0, VecXY(1, 1), 0},
+		{VecXY(3, -2), 0.7, VecXY(2, 0.5), 0},
+		{VecXY(3, -2), -2, VecXY(2, -0.5), 0.3},
+		{VecXY(-1, 4), 3, VecXY(0.1, 10), -1.5},
+	}
+
+	for i, c := range cases {
+		a := AffineCompose(c.translate, c.rad, c.scale, c.shear)
+		translate, rad, scale, shear := a.Decompose()
+		if !translate.Equals(c.translate, e) || !fEqual(rad, c.rad) || !scale.Equals(c.scale, e) || !fEqual(shear, c.shear) {
+			t.Errorf("case %d: got %s, %f, %s, %f, want %s, %f, %s, %f",
+				i, translate, rad, scale, shear, c.translate, c.rad, c.scale, c.shear)
+		}
+		if got := AffineCompose(translate, rad, scale, shear); !got.Equals(a, e) {
+			t.Errorf("case %d: recomposed %s, want %s", i, got, a)
+		}
+	}
+}
+
+func TestAffineApplyShapes(t *testing.T) {
+	a := AffineRotate(math.Pi/4).Translated(1, 2)
+
+	ray := a.ApplyRay(Ray{Origin: VecXY(1, 0), Direction: VecXY(2, 0)})
+	wantRay := Ray{Origin: VecXY(1+math.Sqrt2/2, 2-math.Sqrt2/2), Direction: VecXY(math.Sqrt2, -math.Sqrt2)}
+	if !ray.Origin.Equals(wantRay.Origin, e) || !ray.Direction.Equals(wantRay.Direction, e) {
+		t.Errorf("ray: got %s, want %s", ray, wantRay)
+	}
+
+	rect := a.ApplyRect(RectXYWH(-1, -1, 2, 2))
+	wantRect := RectXYWH(1-math.Sqrt2, 2-math.Sqrt2, 2*math.Sqrt2, 2*math.Sqrt2)
+	if !VecXY(rect.TopLeft()).Equals(VecXY(wantRect.TopLeft()), e) ||
+		!VecXY(rect.Size()).Equals(VecXY(wantRect.Size()), e) {
+		t.Errorf("rect: got %s, want %s", rect, wantRect)
+	}
+
+	circle := a.Scaled(2, 3).ApplyCircle(CircleXYR(1, 0, 2))
+	wantCircle := CircleXYR(2*(1+math.Sqrt2/2), 3*(2-math.Sqrt2/2), 6)
+	if !circle.Equals(wantCircle, e) {
+		t.Errorf("circle: got %s, want %s", circle, wantCircle)
+	}
+
+	poly := AffineTranslate(1, 2).ApplyPolygon(Polygon{VecXY(0, 0), VecXY(1, 0), VecXY(0, 1)})
+	wantPoly := Polygon{VecXY(1, 2), VecXY(2, 2), VecXY(1, 3)}
+	if !poly.Equals(wantPoly, e) {
+		t.Errorf("polygon: got %s, want %s", poly, wantPoly)
+	}
+}
@@ -0,0 +1,284 @@
+package geo
+
+import "sort"
+
+// WrapMode controls how an AnimCurve is evaluated before its first Keyframe or after its
+// last.
+type WrapMode int
+
+const (
+	// WrapClamp holds the value of the nearest Keyframe.
+	WrapClamp WrapMode = iota
+	// WrapLoop repeats the curve from the start.
+	WrapLoop
+	// WrapPingPong repeats the curve, playing every other repeat backwards.
+	WrapPingPong
+)
+
+// CurveWrap holds the WrapModes of an AnimCurve. Its methods are shared by AnimCurve and
+// AnimCurveVec.
+type CurveWrap struct {
+	// PreWrap is used for times before the first Keyframe.
+	PreWrap WrapMode
+	// PostWrap is used for times after the last Keyframe.
+	PostWrap WrapMode
+}
+
+// wrapTime maps t into the range start to end according to the WrapModes.
+func (w CurveWrap) wrapTime(t, start, end float64) float64 {
+	mode := w.PreWrap
+	switch {
+	case t > end:
+		mode = w.PostWrap
+	case t >= start:
+		return t
+	}
+
+	length := end - start
+	if length <= 0 {
+		return start
+	}
+	switch mode {
+	case WrapLoop:
+		return start + Mod(t-start, length)
+	case WrapPingPong:
+		m := Mod(t-start, 2*length)
+		if m > length {
+			m = 2*length - m
+		}
+		return start + m
+	}
+	return Clamp(t, start, end)
+}
+
+// findSegment returns the index of the Keyframe that starts the segment containing t, given
+// n Keyframes sorted by time. t is expected to be within the range of the Keyframes.
+func findSegment(n int, t float64, time func(i int) float64) int {
+	i := sort.Search(n, func(i int) bool { return time(i) > t }) - 1
+	if i > n-2 {
+		i = n - 2
+	}
+	if i < 0 {
+		i = 0
+	}
+	return i
+}
+
+// hermite returns the weights of the start value, start tangent, end value and end tangent of
+// a cubic Hermite curve at s between 0 and 1.
+func hermite(s float64) (h00, h10, h01, h11 float64) {
+	s2, s3 := s*s, s*s*s
+	return 2*s3 - 3*s2 + 1, s3 - 2*s2 + s, -2*s3 + 3*s2, s3 - s2
+}
+
+// Keyframe is a float64 Value at a Time on an AnimCurve.
+type Keyframe struct {
+	Time, Value float64
+	// Ease, if not nil, is followed from this Keyframe to the next. Otherwise the segment is
+	// a cubic Hermite curve using the tangents.
+	Ease EaseFn
+	// InTangent and OutTangent are the slopes, in value per unit of time, of the curve
+	// arriving at and leaving this Keyframe.
+	InTangent, OutTangent float64
+}
+
+// AnimCurve is a float64 value that changes over time, passing through a set of Keyframes,
+// like animation curves in Unity or Blender. The zero value is an empty curve that clamps at
+// both ends.
+type AnimCurve struct {
+	keys []Keyframe
+	CurveWrap
+}
+
+// NewAnimCurve creates an AnimCurve with the given Keyframes, which may be in any order.
+func NewAnimCurve(keys ...Keyframe) *AnimCurve {
+	c := &AnimCurve{}
+	for _, k := range keys {
+		c.Insert(k)
+	}
+	return c
+}
+
+// Len returns the number of Keyframes.
+func (c *AnimCurve) Len() int {
+	return len(c.keys)
+}
+
+// Key returns the i'th Keyframe, in order of time.
+func (c *AnimCurve) Key(i int) Keyframe {
+	return c.keys[i]
+}
+
+// Insert adds k to the curve and returns its index. If there is already a Keyframe at the
+// same time then it is replaced.
+func (c *AnimCurve) Insert(k Keyframe) int {
+	i := sort.Search(len(c.keys), func(i int) bool { return c.keys[i].Time >= k.Time })
+	if i < len(c.keys) && c.keys[i].Time == k.Time {
+		c.keys[i] = k
+		return i
+	}
+	c.keys = append(c.keys, Keyframe{})
+	copy(c.keys[i+1:], c.keys[i:])
+	c.keys[i] = k
+	return i
+}
+
+// Remove deletes the i'th Keyframe and returns true, or returns false if there is no such
+// Keyframe.
+func (c *AnimCurve) Remove(i int) bool {
+	if i < 0 || i >= len(c.keys) {
+		return false
+	}
+	c.keys = append(c.keys[:i], c.keys[i+1:]...)
+	return true
+}
+
+// SmoothTangents sets the tangents of every Keyframe to the slope between its neighbours, or
+// to the slope to its only neighbour at the ends, giving a smooth curve through them.
+func (c *AnimCurve) SmoothTangents() {
+	n := len(c.keys)
+	for i := range c.keys {
+		prev, next := i-1, i+1
+		if prev < 0 {
+			prev = 0
+		}
+		if next > n-1 {
+			next = n - 1
+		}
+		m := 0.0
+		if dt := c.keys[next].Time - c.keys[prev].Time; dt > 0 {
+			m = (c.keys[next].Value - c.keys[prev].Value) / dt
+		}
+		c.keys[i].InTangent, c.keys[i].OutTangent = m, m
+	}
+}
+
+// At returns the value of the curve at time t, or 0 if there are no Keyframes.
+func (c *AnimCurve) At(t float64) float64 {
+	n := len(c.keys)
+	if n == 0 {
+		return 0
+	}
+	if n == 1 {
+		return c.keys[0].Value
+	}
+	t = c.wrapTime(t, c.keys[0].Time, c.keys[n-1].Time)
+	i := findSegment(n, t, func(i int) float64 { return c.keys[i].Time })
+	k0, k1 := c.keys[i], c.keys[i+1]
+	dt := k1.Time - k0.Time
+	s := (t - k0.Time) / dt
+	if k0.Ease != nil {
+		return Ease(k0.Value, k1.Value, s, k0.Ease)
+	}
+	h00, h10, h01, h11 := hermite(s)
+	return h00*k0.Value + h10*dt*k0.OutTangent + h01*k1.Value + h11*dt*k1.InTangent
+}
+
+// KeyframeVec is a Vec Value at a Time on an AnimCurveVec.
+type KeyframeVec struct {
+	Time  float64
+	Value Vec
+	// Ease, if not nil, is followed from this KeyframeVec to the next. Otherwise the segment
+	// is a cubic Hermite curve using the tangents.
+	Ease EaseFn
+	// InTangent and OutTangent are the velocities, in value per unit of time, of the curve
+	// arriving at and leaving this KeyframeVec.
+	InTangent, OutTangent Vec
+}
+
+// AnimCurveVec is a Vec value that changes over time, passing through a set of
+// KeyframeVecs. The zero value is an empty curve that clamps at both ends.
+type AnimCurveVec struct {
+	keys []KeyframeVec
+	CurveWrap
+}
+
+// NewAnimCurveVec creates an AnimCurveVec with the given KeyframeVecs, which may be in any
+// order.
+func NewAnimCurveVec(keys ...KeyframeVec) *AnimCurveVec {
+	c := &AnimCurveVec{}
+	for _, k := range keys {
+		c.Insert(k)
+	}
+	return c
+}
+
+// Len returns the number of KeyframeVecs.
+func (c *AnimCurveVec) Len() int {
+	return len(c.keys)
+}
+
+// Key returns the i'th KeyframeVec, in order of time.
+func (c *AnimCurveVec) Key(i int) KeyframeVec {
+	return c.keys[i]
+}
+
+// Insert adds k to the curve and returns its index. If there is already a KeyframeVec at
+// the same time then it is replaced.
+func (c *AnimCurveVec) Insert(k KeyframeVec) int {
+	i := sort.Search(len(c.keys), func(i int) bool { return c.keys[i].Time >= k.Time })
+	if i < len(c.keys) && c.keys[i].Time == k.Time {
+		c.keys[i] = k
+		return i
+	}
+	c.keys = append(c.keys, KeyframeVec{})
+	copy(c.keys[i+1:], c.keys[i:])
+	c.keys[i] = k
+	return i
+}
+
+// Remove deletes the i'th KeyframeVec and returns true, or returns false if there is no
+// such KeyframeVec.
+func (c *AnimCurveVec) Remove(i int) bool {
+	if i < 0 || i >= len(c.keys) {
+		return false
+	}
+	c.keys = append(c.keys[:i], c.keys[i+1:]...)
+	return true
+}
+
+// SmoothTangents sets the tangents of every KeyframeVec to the velocity between its
+// neighbours, or to the velocity to its only neighbour at the ends, giving a smooth curve
+// through them.
+func (c *AnimCurveVec) SmoothTangents() {
+	n := len(c.keys)
+	for i := range c.keys {
+		prev, next := i-1, i+1
+		if prev < 0 {
+			prev = 0
+		}
+		if next > n-1 {
+			next = n - 1
+		}
+		var m Vec
+		if dt := c.keys[next].Time - c.keys[prev].Time; dt > 0 {
+			m = c.keys[next].Value.Minus(c.keys[prev].Value).DividedBy(dt)
+		}
+		c.keys[i].InTangent, c.keys[i].OutTangent = m, m
+	}
+}
+
+// At returns the value of the curve at time t, or the zero Vec if there are no
+// KeyframeVecs.
+func (c *AnimCurveVec) At(t float64) Vec {
+	n := len(c.keys)
+	if n == 0 {
+		return Vec{}
+	}
+	if n == 1 {
+		return c.keys[0].Value
+	}
+	t = c.wrapTime(t, c.keys[0].Time, c.keys[n-1].Time)
+	i := findSegment(n, t, func(i int) float64 { return c.keys[i].Time })
+	k0, k1 := c.keys[i], c.keys[i+1]
+	dt := k1.Time - k0.Time
+	s := (t - k0.Time) / dt
+	if k0.Ease != nil {
+		return EaseVec(k0.Value, k1.Value, s, k0.Ease)
+	}
+	h00, h10, h01, h11 := hermite(s)
+	return k0.Value.Times(h00).
+		Plus(k0.OutTangent.Times(h10 * dt)).
+		Plus(k1.Value.Times(h01)).
+		Plus(k1.InTangent.Times(h11 * dt))
+}
@@ -0,0 +1,145 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnimCurveAt(t *testing.T) {
+	c := NewAnimCurve(
+		Keyframe{Time: 3, Value: 4, Ease: EaseLinear},
+		Keyframe{Time: 1, Value: 0, Ease: EaseIn(2)},
+		Keyframe{Time: 5, Value: 0, InTangent: -2},
+		Keyframe{Time: 2, Value: 2, OutTangent: 0, InTangent: 0},
+	)
+	cases := []struct {
+		pre, post WrapMode
+		t         float64
+		want      float64
+	}{
+		{WrapClamp, WrapClamp, 1, 0},
+		{WrapClamp, WrapClamp, 1.5, 0.5},
+		{WrapClamp, WrapClamp, 2, 2},
+		// Flat tangents on both ends makes smoothstep.
+		{WrapClamp, WrapClamp, 2.25, 2.3125},
+		{WrapClamp, WrapClamp, 2.5, 3},
+		{WrapClamp, WrapClamp, 3, 4},
+		{WrapClamp, WrapClamp, 4, 2},
+		{WrapClamp, WrapClamp, 5, 0},
+		{WrapClamp, WrapClamp, -10, 0},
+		{WrapClamp, WrapClamp, 10, 0},
+		{WrapLoop, WrapClamp, 0, 2},
+		{WrapLoop, WrapClamp, -6.5, 0.5},
+		{WrapClamp, WrapLoop, 6.5, 3},
+		{WrapClamp, WrapLoop, 9, 0},
+		{WrapPingPong, WrapClamp, 0, 2},
+		{WrapPingPong, WrapClamp, -1, 4},
+		{WrapClamp, WrapPingPong, 8, 2},
+		{WrapClamp, WrapPingPong, 10.5, 3},
+		{WrapClamp, WrapPingPong, 13, 0},
+	}
+
+	for i, tc := range cases {
+		c.PreWrap, c.PostWrap = tc.pre, tc.post
+		if got := c.At(tc.t); math.Abs(got-tc.want) > e {
+			t.Errorf("case %d: got %f, want %f", i, got, tc.want)
+		}
+	}
+
+	// The Hermite segment leaves with the given tangent.
+	c.PreWrap, c.PostWrap = WrapClamp, WrapClamp
+	c.Insert(Keyframe{Time: 3, Value: 4, OutTangent: 1})
+	if got := (c.At(3+1e-6) - c.At(3)) / 1e-6; math.Abs(got-1) > 1e-4 {
+		t.Errorf("tangent: got %f, want %f", got, 1.0)
+	}
+}
+
+func TestAnimCurveEdit(t *testing.T) {
+	c := &AnimCurve{}
+	if got := c.At(1); got != 0 {
+		t.Errorf("empty: got %f, want 0", got)
+	}
+
+	inserts := []struct {
+		k    Keyframe
+		want int
+	}{
+		{Keyframe{Time: 2, Value: 7}, 0},
+		{Keyframe{Time: 4, Value: 1}, 1},
+		{Keyframe{Time: 0, Value: 3}, 0},
+		{Keyframe{Time: 3, Value: 5}, 2},
+		{Keyframe{Time: 4, Value: 9}, 3},
+	}
+	for i, ins := range inserts {
+		if got := c.Insert(ins.k); got != ins.want {
+			t.Errorf("insert %d: got %d, want %d", i, got, ins.want)
+		}
+	}
+	wantTimes := []float64{0, 2, 3, 4}
+	wantValues := []float64{3, 7, 5, 9}
+	if c.Len() != len(wantTimes) {
+		t.Fatalf("len: got %d, want %d", c.Len(), len(wantTimes))
+	}
+	for i := range wantTimes {
+		if k := c.Key(i); k.Time != wantTimes[i] || k.Value != wantValues[i] {
+			t.Errorf("key %d: got %f, %f, want %f, %f", i, k.Time, k.Value, wantTimes[i], wantValues[i])
+		}
+	}
+
+	if c.Remove(4) || c.Remove(-1) {
+		t.Errorf("removed a key that doesn't exist")
+	}
+	if !c.Remove(1) || c.Len() != 3 || c.Key(1).Time != 3 {
+		t.Errorf("remove: got len %d, key 1 at %f", c.Len(), c.Key(1).Time)
+	}
+	c.Remove(0)
+	c.Remove(0)
+	if got := c.At(-5); got != 9 {
+		t.Errorf("single key: got %f, want %f", got, 9.0)
+	}
+
+	// Smooth tangents follow a straight line exactly.
+	line := NewAnimCurve(Keyframe{Time: 0, Value: 1}, Keyframe{Time: 1, Value: 3}, Keyframe{Time: 3, Value: 7})
+	line.SmoothTangents()
+	for x := 0.0; x <= 3; x += 0.25 {
+		if got := line.At(x); math.Abs(got-(1+2*x)) > e {
+			t.Errorf("smooth %f: got %f, want %f", x, got, 1+2*x)
+		}
+	}
+}
+
+func TestAnimCurveVec(t *testing.T) {
+	keys := []struct {
+		time float64
+		x, y float64
+		ease EaseFn
+	}{
+		{0, 0, 10, EaseInOutSine},
+		{1, 5, -5, nil},
+		{3, -2, 4, nil},
+		{4, 8, 8, nil},
+	}
+	cv := &AnimCurveVec{CurveWrap: CurveWrap{PreWrap: WrapLoop, PostWrap: WrapPingPong}}
+	cx := &AnimCurve{CurveWrap: cv.CurveWrap}
+	cy := &AnimCurve{CurveWrap: cv.CurveWrap}
+	for _, k := range keys {
+		cv.Insert(KeyframeVec{Time: k.time, Value: VecXY(k.x, k.y), Ease: k.ease})
+		cx.Insert(Keyframe{Time: k.time, Value: k.x, Ease: k.ease})
+		cy.Insert(Keyframe{Time: k.time, Value: k.y, Ease: k.ease})
+	}
+	cv.SmoothTangents()
+	cx.SmoothTangents()
+	cy.SmoothTangents()
+	cv.Remove(3)
+	cx.Remove(3)
+	cy.Remove(3)
+
+	for x := -4.0; x <= 8; x += 0.3 {
+		if got, want := cv.At(x), VecXY(cx.At(x), cy.At(x)); !got.Equals(want, e) {
+			t.Errorf("%f: got %s, want %s", x, got, want)
+		}
+	}
+	if got := NewAnimCurveVec().At(1); got != (Vec{}) {
+		t.Errorf("empty: got %s, want %s", got, Vec{})
+	}
+}
@@ -0,0 +1,432 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// QuadBezier is a quadratic Bézier curve that starts at P0, ends at P2, and is pulled towards
+// the control point P1. Many of the methods of QuadBezier describe positions along it with a
+// value t, where t=0 is P0 and t=1 is P2.
+type QuadBezier struct {
+	P0, P1, P2 Vec
+}
+
+func (b QuadBezier) String() string {
+	return fmt.Sprintf("QuadBezier(%s, %s, %s)", b.P0, b.P1, b.P2)
+}
+
+// Equals returns true if the corresponding points of the QuadBeziers are within error e.
+func (b QuadBezier) Equals(other QuadBezier, e float64) bool {
+	return b.P0.Equals(other.P0, e) && b.P1.Equals(other.P1, e) && b.P2.Equals(other.P2, e)
+}
+
+// At returns the point on the curve at t.
+func (b QuadBezier) At(t float64) Vec {
+	u := 1 - t
+	return b.P0.Times(u * u).Plus(b.P1.Times(2 * u * t)).Plus(b.P2.Times(t * t))
+}
+
+// Derivative returns the velocity of the curve at t, which is how fast and in which
+// direction At changes with t.
+func (b QuadBezier) Derivative(t float64) Vec {
+	return b.P1.Minus(b.P0).Times(2 * (1 - t)).Plus(b.P2.Minus(b.P1).Times(2 * t))
+}
+
+// derivative2 returns the second derivative of the curve, which is the same for all t.
+func (b QuadBezier) derivative2() Vec {
+	return b.P0.Minus(b.P1.Times(2)).Plus(b.P2).Times(2)
+}
+
+// Tangent returns the unit vector in the direction of the curve at t. Where the curve has no
+// direction, like at the end of a curve whose control point is the same as that end, the
+// direction just next to t is used instead. If the curve is a single point then the zero Vec
+// is returned.
+func (b QuadBezier) Tangent(t float64) Vec {
+	return tangent(b.Derivative, t)
+}
+
+// Normal returns the unit vector perpendicular to the curve at t, which points to the right
+// of the direction of travel.
+func (b QuadBezier) Normal(t float64) Vec {
+	tan := b.Tangent(t)
+	return Vec{X: -tan.Y, Y: tan.X}
+}
+
+// Split divides the curve at t into two curves that together follow the same path.
+func (b QuadBezier) Split(t float64) (QuadBezier, QuadBezier) {
+	p01 := LerpVec(b.P0, b.P1, t)
+	p12 := LerpVec(b.P1, b.P2, t)
+	mid := LerpVec(p01, p12, t)
+	return QuadBezier{b.P0, p01, mid}, QuadBezier{mid, p12, b.P2}
+}
+
+// Cubic returns the CubicBezier that follows the same path as the curve.
+func (b QuadBezier) Cubic() CubicBezier {
+	return CubicBezier{
+		P0: b.P0,
+		P1: LerpVec(b.P0, b.P1, 2.0/3),
+		P2: LerpVec(b.P2, b.P1, 2.0/3),
+		P3: b.P2,
+	}
+}
+
+// BoundingRect returns the smallest Rect that surrounds the curve.
+func (b QuadBezier) BoundingRect() Rect {
+	r := RectCornersVec(b.P0, b.P2).Normalized()
+	for _, axis := range []struct{ p0, p1, p2 float64 }{
+		{b.P0.X, b.P1.X, b.P2.X},
+		{b.P0.Y, b.P1.Y, b.P2.Y},
+	} {
+		// The derivative is linear so there is at most one turning point on each axis.
+		if d := axis.p0 - 2*axis.p1 + axis.p2; d != 0 {
+			if t := (axis.p0 - axis.p1) / d; t > 0 && t < 1 {
+				r = unionPoint(r, b.At(t))
+			}
+		}
+	}
+	return r
+}
+
+// ClosestPoint returns the point on the curve that is closest to v.
+func (b QuadBezier) ClosestPoint(v Vec) Vec {
+	return b.At(b.ClosestT(v))
+}
+
+// ClosestT returns the t value of the point on the curve that is closest to v.
+func (b QuadBezier) ClosestT(v Vec) float64 {
+	d2 := b.derivative2()
+	return closestT(v, b.At, b.Derivative, func(float64) Vec { return d2 }, 16)
+}
+
+// DistPoint returns the shortest distance from the curve to v.
+func (b QuadBezier) DistPoint(v Vec) float64 {
+	return b.ClosestPoint(v).Dist(v)
+}
+
+// Len returns the length of the curve.
+func (b QuadBezier) Len() float64 {
+	return b.LenTo(1)
+}
+
+// LenTo returns the length of the curve from the start to t.
+func (b QuadBezier) LenTo(t float64) float64 {
+	return arcLength(b.Derivative, 0, t)
+}
+
+// TAtLen returns the t value of the point that is dist along the curve from the start, which
+// moves along the curve at a constant speed as dist changes. dist is clamped to the length of
+// the curve.
+func (b QuadBezier) TAtLen(dist float64) float64 {
+	return tAtLen(dist, b.Len(), b.LenTo, b.Derivative)
+}
+
+// AtLen returns the point that is dist along the curve from the start.
+func (b QuadBezier) AtLen(dist float64) Vec {
+	return b.At(b.TAtLen(dist))
+}
+
+// Flatten returns points along the curve, including both ends, such that the lines between
+// them are never more than tolerance away from the curve.
+func (b QuadBezier) Flatten(tolerance float64) []Vec {
+	return b.flatten([]Vec{b.P0}, tolerance, 0)
+}
+
+func (b QuadBezier) flatten(points []Vec, tolerance float64, depth int) []Vec {
+	// The curve is inside the triangle of its points, so it's flat enough if the control
+	// point is close enough to the line between the ends.
+	chord := Segment{A: b.P0, B: b.P2}
+	if depth >= maxFlattenDepth || chord.DistPoint(b.P1) <= tolerance {
+		return append(points, b.P2)
+	}
+	first, second := b.Split(0.5)
+	points = first.flatten(points, tolerance, depth+1)
+	return second.flatten(points, tolerance, depth+1)
+}
+
+// CubicBezier is a cubic Bézier curve that starts at P0, ends at P3, and is pulled towards
+// the control points P1 and P2. Many of the methods of CubicBezier describe positions along
+// it with a value t, where t=0 is P0 and t=1 is P3.
+type CubicBezier struct {
+	P0, P1, P2, P3 Vec
+}
+
+func (b CubicBezier) String() string {
+	return fmt.Sprintf("CubicBezier(%s, %s, %s, %s)", b.P0, b.P1, b.P2, b.P3)
+}
+
+// Equals returns true if the corresponding points of the CubicBeziers are within error e.
+func (b CubicBezier) Equals(other CubicBezier, e float64) bool {
+	return b.P0.Equals(other.P0, e) && b.P1.Equals(other.P1, e) &&
+		b.P2.Equals(other.P2, e) && b.P3.Equals(other.P3, e)
+}
+
+// At returns the point on the curve at t.
+func (b CubicBezier) At(t float64) Vec {
+	u := 1 - t
+	return b.P0.Times(u * u * u).
+		Plus(b.P1.Times(3 * u * u * t)).
+		Plus(b.P2.Times(3 * u * t * t)).
+		Plus(b.P3.Times(t * t * t))
+}
+
+// Derivative returns the velocity of the curve at t, which is how fast and in which
+// direction At changes with t.
+func (b CubicBezier) Derivative(t float64) Vec {
+	u := 1 - t
+	return b.P1.Minus(b.P0).Times(3 * u * u).
+		Plus(b.P2.Minus(b.P1).Times(6 * u * t)).
+		Plus(b.P3.Minus(b.P2).Times(3 * t * t))
+}
+
+// derivative2 returns the second derivative of the curve at t.
+func (b CubicBezier) derivative2(t float64) Vec {
+	a := b.P0.Minus(b.P1.Times(2)).Plus(b.P2)
+	c := b.P1.Minus(b.P2.Times(2)).Plus(b.P3)
+	return a.Times(6 * (1 - t)).Plus(c.Times(6 * t))
+}
+
+// Tangent returns the unit vector in the direction of the curve at t. Where the curve has no
+// direction, like at the end of a curve whose control point is the same as that end, the
+// direction just next to t is used instead. If the curve is a single point then the zero Vec
+// is returned.
+func (b CubicBezier) Tangent(t float64) Vec {
+	return tangent(b.Derivative, t)
+}
+
+// Normal returns the unit vector perpendicular to the curve at t, which points to the right
+// of the direction of travel.
+func (b CubicBezier) Normal(t float64) Vec {
+	tan := b.Tangent(t)
+	return Vec{X: -tan.Y, Y: tan.X}
+}
+
+// Split divides the curve at t into two curves that together follow the same path.
+func (b CubicBezier) Split(t float64) (CubicBezier, CubicBezier) {
+	p01 := LerpVec(b.P0, b.P1, t)
+	p12 := LerpVec(b.P1, b.P2, t)
+	p23 := LerpVec(b.P2, b.P3, t)
+	p012 := LerpVec(p01, p12, t)
+	p123 := LerpVec(p12, p23, t)
+	mid := LerpVec(p012, p123, t)
+	return CubicBezier{b.P0, p01, p012, mid}, CubicBezier{mid, p123, p23, b.P3}
+}
+
+// BoundingRect returns the smallest Rect that surrounds the curve.
+func (b CubicBezier) BoundingRect() Rect {
+	r := RectCornersVec(b.P0, b.P3).Normalized()
+	for _, axis := range []struct{ p0, p1, p2, p3 float64 }{
+		{b.P0.X, b.P1.X, b.P2.X, b.P3.X},
+		{b.P0.Y, b.P1.Y, b.P2.Y, b.P3.Y},
+	} {
+		// The derivative is qa*t^2 + qb*t + qc, so there are at most two turning points on
+		// each axis.
+		d0, d1, d2 := axis.p1-axis.p0, axis.p2-axis.p1, axis.p3-axis.p2
+		qa, qb, qc := d0-2*d1+d2, 2*(d1-d0), d0
+		for _, t := range quadraticRoots(qa, qb, qc) {
+			if t > 0 && t < 1 {
+				r = unionPoint(r, b.At(t))
+			}
+		}
+	}
+	return r
+}
+
+// ClosestPoint returns the point on the curve that is closest to v.
+func (b CubicBezier) ClosestPoint(v Vec) Vec {
+	return b.At(b.ClosestT(v))
+}
+
+// ClosestT returns the t value of the point on the curve that is closest to v.
+func (b CubicBezier) ClosestT(v Vec) float64 {
+	return closestT(v, b.At, b.Derivative, b.derivative2, 32)
+}
+
+// DistPoint returns the shortest distance from the curve to v.
+func (b CubicBezier) DistPoint(v Vec) float64 {
+	return b.ClosestPoint(v).Dist(v)
+}
+
+// Len returns the length of the curve.
+func (b CubicBezier) Len() float64 {
+	return b.LenTo(1)
+}
+
+// LenTo returns the length of the curve from the start to t.
+func (b CubicBezier) LenTo(t float64) float64 {
+	return arcLength(b.Derivative, 0, t)
+}
+
+// TAtLen returns the t value of the point that is dist along the curve from the start, which
+// moves along the curve at a constant speed as dist changes. dist is clamped to the length of
+// the curve.
+func (b CubicBezier) TAtLen(dist float64) float64 {
+	return tAtLen(dist, b.Len(), b.LenTo, b.Derivative)
+}
+
+// AtLen returns the point that is dist along the curve from the start.
+func (b CubicBezier) AtLen(dist float64) Vec {
+	return b.At(b.TAtLen(dist))
+}
+
+// Flatten returns points along the curve, including both ends, such that the lines between
+// them are never more than tolerance away from the curve.
+func (b CubicBezier) Flatten(tolerance float64) []Vec {
+	return b.flatten([]Vec{b.P0}, tolerance, 0)
+}
+
+func (b CubicBezier) flatten(points []Vec, tolerance float64, depth int) []Vec {
+	// The curve is inside the hull of its points, so it's flat enough if the control points
+	// are close enough to the line between the ends.
+	chord := Segment{A: b.P0, B: b.P3}
+	if depth >= maxFlattenDepth ||
+		(chord.DistPoint(b.P1) <= tolerance && chord.DistPoint(b.P2) <= tolerance) {
+		return append(points, b.P3)
+	}
+	first, second := b.Split(0.5)
+	points = first.flatten(points, tolerance, depth+1)
+	return second.flatten(points, tolerance, depth+1)
+}
+
+// maxFlattenDepth limits how many times a curve is split while flattening, so that a
+// tolerance of 0 doesn't go on forever.
+const maxFlattenDepth = 16
+
+// unionPoint returns r grown to include v.
+func unionPoint(r Rect, v Vec) Rect {
+	return RectCorners(math.Min(r.X, v.X), math.Min(r.Y, v.Y),
+		math.Max(r.X+r.W, v.X), math.Max(r.Y+r.H, v.Y))
+}
+
+// quadraticRoots returns the real solutions of a*t^2 + b*t + c = 0.
+func quadraticRoots(a, b, c float64) []float64 {
+	if math.Abs(a) < 1e-12 {
+		if b == 0 {
+			return nil
+		}
+		return []float64{-c / b}
+	}
+	disc := b*b - 4*a*c
+	if disc < 0 {
+		return nil
+	}
+	sq := math.Sqrt(disc)
+	return []float64{(-b - sq) / (2 * a), (-b + sq) / (2 * a)}
+}
+
+// tangent returns the normalized derivative at t, or if it has no length then the direction
+// of the curve just next to t.
+func tangent(deriv func(t float64) Vec, t float64) Vec {
+	d := deriv(t)
+	if d.Len2() == 0 {
+		const h = 1e-6
+		if t+h <= 1 {
+			d = deriv(t + h)
+		} else {
+			d = deriv(t - h)
+		}
+	}
+	if d.Len2() == 0 {
+		return Vec{}
+	}
+	return d.Normalized()
+}
+
+// closestT returns the t value of the point on a curve that is closest to v. It finds the
+// closest of a number of evenly spaced samples and then refines it with Newton's method.
+func closestT(v Vec, at, d1, d2 func(t float64) Vec, samples int) float64 {
+	bestT, best := 0.0, math.Inf(1)
+	for i := 0; i <= samples; i++ {
+		t := float64(i) / float64(samples)
+		if d := at(t).Dist2(v); d < best {
+			bestT, best = t, d
+		}
+	}
+
+	t := bestT
+	for i := 0; i < 8; i++ {
+		diff := at(t).Minus(v)
+		deriv := d1(t)
+		// Minimize the distance by finding where its derivative, diff·deriv, is 0.
+		f := diff.Dot(deriv)
+		df := deriv.Dot(deriv) + diff.Dot(d2(t))
+		if df == 0 {
+			break
+		}
+		t = Clamp(t-f/df, 0, 1)
+	}
+	if at(t).Dist2(v) < best {
+		return t
+	}
+	return bestT
+}
+
+// gaussLegendre are the abscissae and weights of 5 point Gauss-Legendre quadrature over -1
+// to 1.
+var gaussLegendre = [5][2]float64{
+	{0, 0.5688888888888889},
+	{-0.5384693101056831, 0.4786286704993665},
+	{0.5384693101056831, 0.4786286704993665},
+	{-0.9061798459386640, 0.2369268850561891},
+	{0.9061798459386640, 0.2369268850561891},
+}
+
+// arcLength returns the length of a curve with the given derivative between t values a and
+// b. The interval is split until the result stops changing.
+func arcLength(deriv func(t float64) Vec, a, b float64) float64 {
+	whole := gaussLength(deriv, a, b)
+	return adaptiveLength(deriv, a, b, whole, 0)
+}
+
+func adaptiveLength(deriv func(t float64) Vec, a, b, whole float64, depth int) float64 {
+	mid := (a + b) / 2
+	left, right := gaussLength(deriv, a, mid), gaussLength(deriv, mid, b)
+	if depth >= 16 || math.Abs(left+right-whole) <= 1e-12*math.Max(1, whole) {
+		return left + right
+	}
+	return adaptiveLength(deriv, a, mid, left, depth+1) +
+		adaptiveLength(deriv, mid, b, right, depth+1)
+}
+
+func gaussLength(deriv func(t float64) Vec, a, b float64) float64 {
+	half, mid := (b-a)/2, (a+b)/2
+	sum := 0.0
+	for _, g := range gaussLegendre {
+		sum += g[1] * deriv(mid+half*g[0]).Len()
+	}
+	return sum * half
+}
+
+// tAtLen returns the t value at which lenTo is dist, given the total length of the curve. It
+// uses Newton's method, falling back to bisection when Newton's method leaves the interval
+// known to contain the answer.
+func tAtLen(dist, total float64, lenTo func(t float64) float64, deriv func(t float64) Vec) float64 {
+	if dist <= 0 || total <= 0 {
+		return 0
+	}
+	if dist >= total {
+		return 1
+	}
+	lo, hi := 0.0, 1.0
+	t := dist / total
+	for i := 0; i < 32; i++ {
+		f := lenTo(t) - dist
+		if math.Abs(f) < 1e-9*total {
+			break
+		}
+		if f > 0 {
+			hi = t
+		} else {
+			lo = t
+		}
+		next := lo - 1
+		if speed := deriv(t).Len(); speed > 0 {
+			next = t - f/speed
+		}
+		if next <= lo || next >= hi {
+			next = (lo + hi) / 2
+		}
+		t = next
+	}
+	return t
+}
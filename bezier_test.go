@@ -0,0 +1,249 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuadBezierAt(t *testing.T) {
+	b := QuadBezier{VecXY(0, 0), VecXY(1, 2), VecXY(2, 0)}
+	cases := []struct {
+		t           float64
+		want, dWant Vec
+	}{
+		{0, VecXY(0, 0), VecXY(2, 4)},
+		{0.5, VecXY(1, 1), VecXY(2, 0)},
+		{1, VecXY(2, 0), VecXY(2, -4)},
+		{0.25, VecXY(0.5, 0.75), VecXY(2, 2)},
+	}
+
+	for i, c := range cases {
+		if got := b.At(c.t); !got.Equals(c.want, e) {
+			t.Errorf("case %d: got %s, want %s", i, got, c.want)
+		}
+		if got := b.Derivative(c.t); !got.Equals(c.dWant, e) {
+			t.Errorf("case %d: derivative: got %s, want %s", i, got, c.dWant)
+		}
+	}
+
+	if got, want := b.Tangent(0.5), VecXY(1, 0); !got.Equals(want, e) {
+		t.Errorf("tangent: got %s, want %s", got, want)
+	}
+	if got, want := b.Normal(0.5), VecXY(0, 1); !got.Equals(want, e) {
+		t.Errorf("normal: got %s, want %s", got, want)
+	}
+	// A control point on the end still has a direction there.
+	flat := QuadBezier{VecXY(0, 0), VecXY(0, 0), VecXY(3, 4)}
+	if got, want := flat.Tangent(0), VecXY(0.6, 0.8); !got.Equals(want, 1e-6) {
+		t.Errorf("degenerate tangent: got %s, want %s", got, want)
+	}
+
+	// The cubic version is the same curve.
+	cubic := b.Cubic()
+	for x := 0.0; x <= 1; x += 0.1 {
+		if got, want := cubic.At(x), b.At(x); !got.Equals(want, e) {
+			t.Errorf("cubic %f: got %s, want %s", x, got, want)
+		}
+	}
+}
+
+func TestCubicBezierAt(t *testing.T) {
+	b := CubicBezier{VecXY(0, 0), VecXY(0, 3), VecXY(3, 3), VecXY(3, 0)}
+	cases := []struct {
+		t           float64
+		want, dWant Vec
+	}{
+		{0, VecXY(0, 0), VecXY(0, 9)},
+		{0.5, VecXY(1.5, 2.25), VecXY(4.5, 0)},
+		{1, VecXY(3, 0), VecXY(0, -9)},
+		{0.25, VecXY(0.46875, 1.6875), VecXY(3.375, 4.5)},
+	}
+
+	for i, c := range cases {
+		if got := b.At(c.t); !got.Equals(c.want, e) {
+			t.Errorf("case %d: got %s, want %s", i, got, c.want)
+		}
+		if got := b.Derivative(c.t); !got.Equals(c.dWant, e) {
+			t.Errorf("case %d: derivative: got %s, want %s", i, got, c.dWant)
+		}
+	}
+
+	if got, want := b.Tangent(1), VecXY(0, -1); !got.Equals(want, e) {
+		t.Errorf("tangent: got %s, want %s", got, want)
+	}
+	if got, want := b.Normal(1), VecXY(1, 0); !got.Equals(want, e) {
+		t.Errorf("normal: got %s, want %s", got, want)
+	}
+	if got := (CubicBezier{}).Tangent(0.5); got != (Vec{}) {
+		t.Errorf("point tangent: got %s, want %s", got, Vec{})
+	}
+}
+
+func TestBezierSplit(t *testing.T) {
+	q := QuadBezier{VecXY(-1, 2), VecXY(4, -3), VecXY(5, 6)}
+	c := CubicBezier{VecXY(-1, 2), VecXY(4, -3), VecXY(-2, 7), VecXY(5, 6)}
+	for _, at := range []float64{0, 0.3, 0.5, 1} {
+		q1, q2 := q.Split(at)
+		c1, c2 := c.Split(at)
+		for x := 0.0; x <= 1; x += 0.125 {
+			if got, want := q1.At(x), q.At(x*at); !got.Equals(want, e) {
+				t.Errorf("quad %f first %f: got %s, want %s", at, x, got, want)
+			}
+			if got, want := q2.At(x), q.At(at+x*(1-at)); !got.Equals(want, e) {
+				t.Errorf("quad %f second %f: got %s, want %s", at, x, got, want)
+			}
+			if got, want := c1.At(x), c.At(x*at); !got.Equals(want, e) {
+				t.Errorf("cubic %f first %f: got %s, want %s", at, x, got, want)
+			}
+			if got, want := c2.At(x), c.At(at+x*(1-at)); !got.Equals(want, e) {
+				t.Errorf("cubic %f second %f: got %s, want %s", at, x, got, want)
+			}
+		}
+	}
+}
+
+func TestBezierBoundingRect(t *testing.T) {
+	cases := []struct {
+		b    CubicBezier
+		want Rect
+	}{
+		{CubicBezier{VecXY(0, 0), VecXY(0, 3), VecXY(3, 3), VecXY(3, 0)}, RectXYWH(0, 0, 3, 2.25)},
+		{CubicBezier{VecXY(3, 0), VecXY(3, -3), VecXY(0, -3), VecXY(0, 0)}, RectXYWH(0, -2.25, 3, 2.25)},
+		{CubicBezier{VecXY(0, 0), VecXY(1, 1), VecXY(2, 2), VecXY(3, 3)}, RectXYWH(0, 0, 3, 3)},
+		// S shaped, with two turning points in y.
+		{CubicBezier{VecXY(0, 0), VecXY(1, 4), VecXY(2, -4), VecXY(3, 0)},
+			RectCorners(0, -math.Sqrt(3)*2/3, 3, math.Sqrt(3)*2/3)},
+	}
+
+	for i, c := range cases {
+		got := c.b.BoundingRect()
+		if math.Abs(got.X-c.want.X) > e || math.Abs(got.Y-c.want.Y) > e ||
+			math.Abs(got.W-c.want.W) > e || math.Abs(got.H-c.want.H) > e {
+			t.Errorf("case %d: got %s, want %s", i, got, c.want)
+		}
+	}
+
+	q := QuadBezier{VecXY(0, 0), VecXY(1, 2), VecXY(2, 0)}
+	if got, want := q.BoundingRect(), RectXYWH(0, 0, 2, 1); got != want {
+		t.Errorf("quad: got %s, want %s", got, want)
+	}
+
+	// Every point on a curve is inside its bounds.
+	b := CubicBezier{VecXY(-1, 2), VecXY(4, -3), VecXY(-2, 7), VecXY(5, 6)}
+	r := b.BoundingRect().Inflated(2*e, 2*e)
+	for x := 0.0; x <= 1; x += 0.01 {
+		if p := b.At(x); !r.CollidePoint(p.X, p.Y) {
+			t.Errorf("%f: %s outside %s", x, p, r)
+		}
+	}
+}
+
+func TestBezierClosestPoint(t *testing.T) {
+	b := CubicBezier{VecXY(0, 0), VecXY(0, 3), VecXY(3, 3), VecXY(3, 0)}
+	cases := []struct {
+		v    Vec
+		want Vec
+	}{
+		{VecXY(1.5, 5), VecXY(1.5, 2.25)},
+		{VecXY(-1, -1), VecXY(0, 0)},
+		{VecXY(5, -1), VecXY(3, 0)},
+		{b.At(0.3), b.At(0.3)},
+	}
+
+	for i, c := range cases {
+		if got := b.ClosestPoint(c.v); !got.Equals(c.want, 1e-9) {
+			t.Errorf("case %d: got %s, want %s", i, got, c.want)
+		}
+	}
+
+	// Nothing on the curve is closer.
+	q := QuadBezier{VecXY(-1, 2), VecXY(4, -3), VecXY(5, 6)}
+	for _, v := range []Vec{VecXY(0, 0), VecXY(3, 3), VecXY(10, -2), VecXY(2, 1)} {
+		qDist, cDist := q.DistPoint(v), b.DistPoint(v)
+		for x := 0.0; x <= 1; x += 0.001 {
+			if d := q.At(x).Dist(v); d < qDist-1e-9 {
+				t.Errorf("quad %s: %f closer than %f", v, d, qDist)
+				break
+			}
+			if d := b.At(x).Dist(v); d < cDist-1e-9 {
+				t.Errorf("cubic %s: %f closer than %f", v, d, cDist)
+				break
+			}
+		}
+	}
+}
+
+func TestBezierLen(t *testing.T) {
+	// A straight line with unevenly spaced control points.
+	line := CubicBezier{VecXY(0, 0), VecXY(0.5, 0), VecXY(1, 0), VecXY(4, 0)}
+	if got := line.Len(); math.Abs(got-4) > e {
+		t.Errorf("line: got %f, want %f", got, 4.0)
+	}
+	for _, dist := range []float64{-1, 0, 1, 2.5, 4, 5} {
+		want := Clamp(dist, 0, 4)
+		if got := line.AtLen(dist); !got.Equals(VecXY(want, 0), 1e-8) {
+			t.Errorf("line %f: got %s, want %s", dist, got, VecXY(want, 0))
+		}
+	}
+
+	// The length of a parabola y = x^2 from 0 to 1.
+	q := QuadBezier{VecXY(0, 0), VecXY(0.5, 0), VecXY(1, 1)}
+	want := math.Sqrt(5)/2 + math.Asinh(2)/4
+	if got := q.Len(); math.Abs(got-want) > e {
+		t.Errorf("parabola: got %f, want %f", got, want)
+	}
+
+	// A quarter circle is approximated closely by this cubic.
+	k := 4.0 / 3 * (math.Sqrt2 - 1)
+	arc := CubicBezier{VecXY(1, 0), VecXY(1, k), VecXY(k, 1), VecXY(0, 1)}
+	if got := arc.Len(); math.Abs(got-math.Pi/2) > 1e-3 {
+		t.Errorf("arc: got %f, want %f", got, math.Pi/2)
+	}
+	for i := 1; i < 10; i++ {
+		dist := arc.Len() * float64(i) / 10
+		if got := arc.LenTo(arc.TAtLen(dist)); math.Abs(got-dist) > 1e-8 {
+			t.Errorf("arc %f: got %f", dist, got)
+		}
+	}
+	if got := q.LenTo(q.TAtLen(0.7)); math.Abs(got-0.7) > 1e-8 {
+		t.Errorf("parabola 0.7: got %f", got)
+	}
+}
+
+func TestBezierFlatten(t *testing.T) {
+	b := CubicBezier{VecXY(-1, 2), VecXY(4, -3), VecXY(-2, 7), VecXY(5, 6)}
+	q := QuadBezier{VecXY(-1, 2), VecXY(4, -3), VecXY(5, 6)}
+	for _, tolerance := range []float64{1, 0.1, 0.01} {
+		for _, c := range []struct {
+			name   string
+			points []Vec
+			at     func(float64) Vec
+		}{
+			{"cubic", b.Flatten(tolerance), b.At},
+			{"quad", q.Flatten(tolerance), q.At},
+		} {
+			if c.points[0] != c.at(0) || c.points[len(c.points)-1] != c.at(1) {
+				t.Errorf("%s %f: ends got %s and %s", c.name, tolerance, c.points[0], c.points[len(c.points)-1])
+			}
+			// Every point on the curve is within tolerance of the polyline.
+			for x := 0.0; x <= 1; x += 0.001 {
+				p := c.at(x)
+				best := math.Inf(1)
+				for i := 1; i < len(c.points); i++ {
+					best = math.Min(best, Segment{A: c.points[i-1], B: c.points[i]}.DistPoint(p))
+				}
+				if best > tolerance+e {
+					t.Errorf("%s %f: %s is %f away", c.name, tolerance, p, best)
+					break
+				}
+			}
+		}
+	}
+
+	if got := len(b.Flatten(0.01)); got <= len(b.Flatten(1)) {
+		t.Errorf("smaller tolerance didn't add points")
+	}
+	if got := (CubicBezier{P3: VecXY(1, 1)}).Flatten(0); len(got) != 2 {
+		t.Errorf("straight: got %v, want 2 points", got)
+	}
+}
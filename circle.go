@@ -3,6 +3,7 @@ package geo
 import (
 	"fmt"
 	"math"
+	"math/rand"
 )
 
 // Circle is a 2-D circle with position (X, Y) and radius R.
@@ -327,34 +328,210 @@ func (c Circle) Unioned(other Circle) Circle {
 	return c
 }
 
-// // EnclosingCircle returns the smallest Circle that encloses all the given points.
-// func EnclosingCircle(points []Vec) Circle {
-// 	shuffled := make([]Vec, len(points))
-// 	perm := rand.Perm(len(points))
-// 	for i, v := range perm {
-// 		shuffled[v] = points[i]
-// 	}
-// 	return enclosingCircle(shuffled, []Vec{})
-// }
-//
-// func enclosingCircle(points []Vec, pointsOnBoundary []Vec) Circle {
-// 	// http://www.sunshine2k.de/coding/java/Welzl/Welzl.html
-// 	// if (P is empty or |R| = 3) then
-// 	//        D := calcDiskDirectly(R)
-// 	//   else
-// 	//       choose a p from P randomly;
-// 	//       D := sed(P - {p}, R);
-// 	//       if (p lies NOT inside D) then
-// 	//           D := sed(P - {p}, R u {p});
-// 	//   return D;
-// 	return Circle{}
-// }
-//
-// // CircleUnion retusn the smallest Circle that encloses all the given Circles.
-// func CircleUnion(circles []Circle) Circle {
-// 	// https://bl.ocks.org/mbostock/29c534ff0b270054a01c
-// 	return Circle{}
-// }
+// welzlPerm returns a random permutation of [0, n) for shuffling the input to Welzl's
+// algorithm. It uses its own source so that it doesn't change the global one.
+func welzlPerm(n int) []int {
+	return rand.New(rand.NewSource(int64(n))).Perm(n)
+}
+
+// EnclosingCircle returns the smallest Circle that encloses all the given points. An empty
+// list returns a Circle with radius 0 at the origin.
+func EnclosingCircle(points []Vec) Circle {
+	// Iterative version of Welzl's algorithm, which runs in expected linear time when the
+	// points are in random order.
+	// http://www.sunshine2k.de/coding/java/Welzl/Welzl.html
+	shuffled := make([]Vec, len(points))
+	for i, j := range welzlPerm(len(points)) {
+		shuffled[j] = points[i]
+	}
+
+	c := Circle{}
+	for i, p := range shuffled {
+		if i > 0 && c.enclosesPoint(p) {
+			continue
+		}
+		c = CircleVecR(p, 0)
+		for j, q := range shuffled[:i] {
+			if c.enclosesPoint(q) {
+				continue
+			}
+			c = circleFrom2(p, q)
+			for _, s := range shuffled[:j] {
+				if !c.enclosesPoint(s) {
+					c = circleFrom3(p, q, s)
+				}
+			}
+		}
+	}
+	return c
+}
+
+// enclosesPoint returns true if v is inside or on the edge of the Circle, allowing for
+// some floating point error.
+func (c Circle) enclosesPoint(v Vec) bool {
+	return c.Pos().Dist(v) <= c.R+math.Max(c.R, 1)*1e-9
+}
+
+// circleFrom2 returns the smallest Circle with both points on its edge.
+func circleFrom2(a, b Vec) Circle {
+	return CircleVecR(LerpVec(a, b, 0.5), a.Dist(b)/2)
+}
+
+// circleFrom3 returns the Circle with all three points on its edge. If the points are
+// collinear then the smallest Circle that encloses all of them is returned.
+func circleFrom3(a, b, c Vec) Circle {
+	ab := b.Minus(a)
+	ac := c.Minus(a)
+	d := 2 * ab.Cross(ac)
+	if d == 0 {
+		// Collinear so the two farthest points define the Circle.
+		circ := circleFrom2(a, b)
+		if other := circleFrom2(a, c); other.R > circ.R {
+			circ = other
+		}
+		if other := circleFrom2(b, c); other.R > circ.R {
+			circ = other
+		}
+		return circ
+	}
+	center := VecXY(
+		(ac.Y*ab.Len2()-ab.Y*ac.Len2())/d,
+		(ab.X*ac.Len2()-ac.X*ab.Len2())/d,
+	)
+	return CircleVecR(center.Plus(a), center.Len())
+}
+
+// CircleUnion returns the smallest Circle that encloses all the given Circles. An empty list
+// returns a Circle with radius 0 at the origin.
+func CircleUnion(circles []Circle) Circle {
+	// Welzl's algorithm extended to circles.
+	// https://bl.ocks.org/mbostock/29c534ff0b270054a01c
+	shuffled := make([]Circle, len(circles))
+	for i, j := range welzlPerm(len(circles)) {
+		shuffled[j] = circles[i].Normalized()
+	}
+
+	var basis []Circle
+	enclosing := Circle{}
+	for i := 0; i < len(shuffled); {
+		c := shuffled[i]
+		if len(basis) > 0 && enclosing.enclosesWeak(c) {
+			i++
+			continue
+		}
+		basis = extendBasis(basis, c)
+		enclosing = encloseBasis(basis)
+		i = 0
+	}
+	return enclosing
+}
+
+// enclosesWeak returns true if other is inside of the Circle, allowing for some floating
+// point error.
+func (c Circle) enclosesWeak(other Circle) bool {
+	dr := c.R - other.R + math.Max(math.Max(c.R, other.R), 1)*1e-9
+	return dr > 0 && dr*dr > c.Pos().Dist2(other.Pos())
+}
+
+// enclosesWeakAll returns true if all of others are inside of the Circle, allowing for
+// some floating point error.
+func (c Circle) enclosesWeakAll(others []Circle) bool {
+	for _, other := range others {
+		if !c.enclosesWeak(other) {
+			return false
+		}
+	}
+	return true
+}
+
+// enclosesNot returns true if other is not completely inside the Circle.
+func (c Circle) enclosesNot(other Circle) bool {
+	dr := c.R - other.R
+	return dr < 0 || dr*dr < c.Pos().Dist2(other.Pos())
+}
+
+// extendBasis returns the smallest set of Circles, including c, whose enclosing Circle
+// encloses all of basis as well.
+func extendBasis(basis []Circle, c Circle) []Circle {
+	if c.enclosesWeakAll(basis) {
+		return []Circle{c}
+	}
+
+	for _, b := range basis {
+		if c.enclosesNot(b) && encloseBasis2(b, c).enclosesWeakAll(basis) {
+			return []Circle{b, c}
+		}
+	}
+
+	for i := 0; i < len(basis)-1; i++ {
+		for j := i + 1; j < len(basis); j++ {
+			bi, bj := basis[i], basis[j]
+			if encloseBasis2(bi, bj).enclosesNot(c) &&
+				encloseBasis2(bi, c).enclosesNot(bj) &&
+				encloseBasis2(bj, c).enclosesNot(bi) &&
+				encloseBasis3(bi, bj, c).enclosesWeakAll(basis) {
+				return []Circle{bi, bj, c}
+			}
+		}
+	}
+
+	// Only reachable due to floating point error, in which case the Circle on its own is the
+	// best that can be done.
+	return []Circle{c}
+}
+
+// encloseBasis returns the smallest Circle that encloses the 1, 2, or 3 Circles in basis.
+func encloseBasis(basis []Circle) Circle {
+	switch len(basis) {
+	case 1:
+		return basis[0]
+	case 2:
+		return encloseBasis2(basis[0], basis[1])
+	}
+	return encloseBasis3(basis[0], basis[1], basis[2])
+}
+
+// encloseBasis2 returns the smallest Circle that encloses both a and b, assuming neither
+// encloses the other.
+func encloseBasis2(a, b Circle) Circle {
+	ab := b.Pos().Minus(a.Pos())
+	l := ab.Len()
+	if l == 0 {
+		return CircleXYR(a.X, a.Y, math.Max(a.R, b.R))
+	}
+	dr := b.R - a.R
+	return CircleXYR(
+		(a.X+b.X+ab.X/l*dr)/2,
+		(a.Y+b.Y+ab.Y/l*dr)/2,
+		(l+a.R+b.R)/2,
+	)
+}
+
+// encloseBasis3 returns the smallest Circle that encloses a, b, and c and is tangent to
+// all of them.
+func encloseBasis3(a, b, c Circle) Circle {
+	a2, a3 := a.X-b.X, a.X-c.X
+	b2, b3 := a.Y-b.Y, a.Y-c.Y
+	c2, c3 := b.R-a.R, c.R-a.R
+	d1 := a.X*a.X + a.Y*a.Y - a.R*a.R
+	d2 := d1 - b.X*b.X - b.Y*b.Y + b.R*b.R
+	d3 := d1 - c.X*c.X - c.Y*c.Y + c.R*c.R
+	ab := a3*b2 - a2*b3
+	xa := (b2*d3-b3*d2)/(ab*2) - a.X
+	xb := (b3*c2 - b2*c3) / ab
+	ya := (a3*d2-a2*d3)/(ab*2) - a.Y
+	yb := (a2*c3 - a3*c2) / ab
+	qa := xb*xb + yb*yb - 1
+	qb := 2 * (a.R + xa*xb + ya*yb)
+	qc := xa*xa + ya*ya - a.R*a.R
+	var r float64
+	if math.Abs(qa) > 1e-6 {
+		r = -(qb + math.Sqrt(qb*qb-4*qa*qc)) / (2 * qa)
+	} else {
+		r = -qc / qb
+	}
+	return CircleXYR(a.X+xa+xb*r, a.Y+ya+yb*r, r)
+}
 
 // BoundingRect returns the smallest Rect that surrounds the Circle.
 func (c Circle) BoundingRect() Rect {
@@ -420,3 +597,156 @@ func (c Circle) CollideRectListAll(rs []Rect) []int {
 	}
 	return list
 }
+
+// CollidePolygon returns true if the Circle is colliding with the Polygon.
+func (c Circle) CollidePolygon(p Polygon) bool {
+	return p.CollideCircle(c)
+}
+
+// CollidePolygonList returns the index of the first Polygon the Circle collides with. If
+// there is no collision then ok is false and i is undefined.
+func (c Circle) CollidePolygonList(ps []Polygon) (i int, ok bool) {
+	for i, p := range ps {
+		if p.CollideCircle(c) {
+			return i, true
+		}
+	}
+	return
+}
+
+// CollidePolygonListAll returns a list of indices of the Polygons that collide with the
+// Circle, or an empty list if none.
+func (c Circle) CollidePolygonListAll(ps []Polygon) []int {
+	list := make([]int, 0, len(ps))
+	for i, p := range ps {
+		if p.CollideCircle(c) {
+			list = append(list, i)
+		}
+	}
+	return list
+}
+
+// PenetrationCircle returns the minimum translation vector (MTV) that would move this Circle
+// out of other, along with the unit contact normal pointing from other towards this Circle.
+// If the Circles don't collide then hit is false and mtv and normal are the zero vector. If
+// the Circles share the same center then the normal points up (-y).
+func (c Circle) PenetrationCircle(other Circle) (mtv, normal Vec, hit bool) {
+	if !c.CollideCircle(other) {
+		return
+	}
+	d := c.Pos().Minus(other.Pos())
+	dist := d.Len()
+	normal = Vec{Y: -1}
+	if dist > 0 {
+		normal = d.DividedBy(dist)
+	}
+	return normal.Times(c.R + other.R - dist), normal, true
+}
+
+// PenetrationRect returns the minimum translation vector (MTV) that would move the Circle
+// out of the Rect, along with the unit contact normal pointing from the Rect towards the
+// Circle. If they don't collide then hit is false and mtv and normal are the zero vector.
+func (c Circle) PenetrationRect(r Rect) (mtv, normal Vec, hit bool) {
+	if !c.CollideRect(r) {
+		return
+	}
+	center := c.Pos()
+	closest := center.Clamped(r)
+	if closest != center {
+		d := center.Minus(closest)
+		dist := d.Len()
+		normal = d.DividedBy(dist)
+		return normal.Times(c.R - dist), normal, true
+	}
+	// The center is inside the Rect so push it out through the nearest edge.
+	r.Normalize()
+	depth, normal := c.X-r.Left(), Vec{X: -1}
+	if d := r.Right() - c.X; d < depth {
+		depth, normal = d, Vec{X: 1}
+	}
+	if d := c.Y - r.Top(); d < depth {
+		depth, normal = d, Vec{Y: -1}
+	}
+	if d := r.Bottom() - c.Y; d < depth {
+		depth, normal = d, Vec{Y: 1}
+	}
+	return normal.Times(depth + c.R), normal, true
+}
+
+// PenetrationPolygon returns the minimum translation vector (MTV) that would move the
+// Circle out of the Polygon, along with the unit contact normal pointing from the Polygon
+// towards the Circle. If they don't collide then hit is false and mtv and normal are the
+// zero vector.
+func (c Circle) PenetrationPolygon(p Polygon) (mtv, normal Vec, hit bool) {
+	mtv, normal, hit = p.PenetrationCircle(c)
+	return mtv.Times(-1), normal.Times(-1), hit
+}
+
+// SweepCircle tests whether this Circle, moving by vel, hits other. It returns the fraction
+// of vel at which they first touch, in [0, 1], along with the unit normal pointing from other
+// towards this Circle at the point of contact. If the Circles already collide then t is 0
+// and normal is the same as from PenetrationCircle. If hit is false then t and normal are
+// undefined.
+func (c Circle) SweepCircle(vel Vec, other Circle) (t float64, normal Vec, hit bool) {
+	if _, normal, hit := c.PenetrationCircle(other); hit {
+		return 0, normal, true
+	}
+	// Sweeping c against other is the same as sweeping c's center against other grown by c's
+	// radius.
+	r := math.Abs(c.R) + math.Abs(other.R)
+	t, hit = CircleXYR(other.X, other.Y, r).sweepPoint(c.Pos(), vel)
+	if !hit {
+		return 0, Vec{}, false
+	}
+	return t, c.Pos().Plus(vel.Times(t)).Minus(other.Pos()).DividedBy(r), true
+}
+
+// SweepRect tests whether the Circle, moving by vel, hits the Rect. It returns the fraction
+// of vel at which they first touch, in [0, 1], along with the unit normal pointing from the
+// Rect towards the Circle at the point of contact. If they already collide then t is 0 and
+// normal is the same as from PenetrationRect. If hit is false then t and normal are undefined.
+func (c Circle) SweepRect(vel Vec, r Rect) (t float64, normal Vec, hit bool) {
+	if _, normal, hit := c.PenetrationRect(r); hit {
+		return 0, normal, true
+	}
+	c.Normalize()
+	r.Normalize()
+	// First sweep the center against the Rect grown by the radius, then if it enters at one
+	// of the grown corners check against the rounded corner instead. Since they don't already
+	// collide, a center that starts inside the grown Rect must be in one of its corners.
+	t, _, normal, hit = r.Inflated(2*c.R, 2*c.R).sweepPoint(c.Pos(), vel)
+	if !hit || t > 1 {
+		return 0, Vec{}, false
+	}
+	p := c.Pos()
+	if t >= 0 {
+		p = p.Plus(vel.Times(t))
+	}
+	corner := p.Clamped(r)
+	if t >= 0 && (corner.X == p.X || corner.Y == p.Y) {
+		return t, normal, true
+	}
+	t, hit = CircleVecR(corner, c.R).sweepPoint(c.Pos(), vel)
+	if !hit {
+		return 0, Vec{}, false
+	}
+	return t, c.Pos().Plus(vel.Times(t)).Minus(corner).DividedBy(c.R), true
+}
+
+// sweepPoint returns the fraction of vel, in [0, 1], at which a point starting at origin
+// and moving by vel first touches the Circle. Grazing the Circle doesn't count as a hit.
+func (c Circle) sweepPoint(origin, vel Vec) (t float64, hit bool) {
+	a := vel.Len2()
+	if a == 0 {
+		return
+	}
+	fromCenter := origin.Minus(c.Pos())
+	b := 2 * fromCenter.Dot(vel)
+	cc := fromCenter.Len2() - c.R*c.R
+	disc := b*b - 4*a*cc
+	if disc <= 0 {
+		return
+	}
+	t = (-b - math.Sqrt(disc)) / (2 * a)
+	return t, t >= 0 && t <= 1
+}
@@ -2,6 +2,7 @@ package geo
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 )
 
@@ -450,3 +451,269 @@ func TestCircleCollideRectListAll(t *testing.T) {
 		}
 	}
 }
+
+func TestCirclePenetrationCircle(t *testing.T) {
+	cases := []struct {
+		c1, c2      Circle
+		mtv, normal Vec
+		hit         bool
+	}{
+		{CircleXYR(0, 0, 1), CircleXYR(3, 0, 1), Vec{}, Vec{}, false},
+		{CircleXYR(0, 0, 1), CircleXYR(2, 0, 1), Vec{}, Vec{}, false},
+		{CircleXYR(0, 0, 1), CircleXYR(1.5, 0, 1), VecXY(-0.5, 0), VecXY(-1, 0), true},
+		{CircleXYR(0, 0, 1), CircleXYR(0, -1, 2), VecXY(0, 2), VecXY(0, 1), true},
+		{CircleXYR(0, 0, 1), CircleXYR(0, 0, 2), VecXY(0, -3), VecXY(0, -1), true},
+	}
+
+	for i, c := range cases {
+		mtv, normal, hit := c.c1.PenetrationCircle(c.c2)
+		if hit != c.hit || !mtv.Equals(c.mtv, e) || !normal.Equals(c.normal, e) {
+			t.Errorf("case %d: got %s, %s, %v, want %s, %s, %v", i, mtv, normal, hit, c.mtv, c.normal, c.hit)
+		}
+	}
+}
+
+func TestCirclePenetrationRect(t *testing.T) {
+	r := RectXYWH(0, 0, 4, 2)
+	cases := []struct {
+		c           Circle
+		r           Rect
+		mtv, normal Vec
+		hit         bool
+	}{
+		{CircleXYR(-2, 1, 1), r, Vec{}, Vec{}, false},
+		{CircleXYR(-1, 1, 1), r, Vec{}, Vec{}, false},
+		{CircleXYR(-0.5, 1, 1), r, VecXY(-0.5, 0), VecXY(-1, 0), true},
+		{CircleXYR(5, 3, 2), r, VecXY(2-math.Sqrt2, 2-math.Sqrt2).Times(math.Sqrt2 / 2), VecXY(1, 1).Normalized(), true},
+		{CircleXYR(1, 0.5, 1), r, VecXY(0, -1.5), VecXY(0, -1), true},
+		{CircleXYR(3.5, 1.2, 1), r, VecXY(1.5, 0), VecXY(1, 0), true},
+		{CircleXYR(2, 1.5, 1), r, VecXY(0, 1.5), VecXY(0, 1), true},
+		{CircleXYR(2, 1.5, 1), RectXYWH(4, 2, -4, -2), VecXY(0, 1.5), VecXY(0, 1), true},
+	}
+
+	for i, c := range cases {
+		mtv, normal, hit := c.c.PenetrationRect(c.r)
+		if hit != c.hit || !mtv.Equals(c.mtv, e) || !normal.Equals(c.normal, e) {
+			t.Errorf("case %d: got %s, %s, %v, want %s, %s, %v", i, mtv, normal, hit, c.mtv, c.normal, c.hit)
+		}
+	}
+}
+
+func TestCirclePenetrationPolygon(t *testing.T) {
+	square := PolygonRect(RectXYWH(0, 0, 4, 2))
+	cases := []struct {
+		c           Circle
+		p           Polygon
+		mtv, normal Vec
+		hit         bool
+	}{
+		{CircleXYR(-1, 1, 1), square, Vec{}, Vec{}, false},
+		{CircleXYR(-0.5, 1, 1), square, VecXY(-0.5, 0), VecXY(-1, 0), true},
+		{CircleXYR(1, 0.5, 1), square, VecXY(0, -1.5), VecXY(0, -1), true},
+	}
+
+	for i, c := range cases {
+		mtv, normal, hit := c.c.PenetrationPolygon(c.p)
+		if hit != c.hit || !mtv.Equals(c.mtv, e) || !normal.Equals(c.normal, e) {
+			t.Errorf("case %d: got %s, %s, %v, want %s, %s, %v", i, mtv, normal, hit, c.mtv, c.normal, c.hit)
+		}
+	}
+}
+
+func TestEnclosingCircle(t *testing.T) {
+	cases := []struct {
+		points []Vec
+		want   Circle
+	}{
+		{[]Vec{}, Circle{}},
+		{[]Vec{VecXY(1, 2)}, CircleXYR(1, 2, 0)},
+		{[]Vec{VecXY(-1, 0), VecXY(3, 0)}, CircleXYR(1, 0, 2)},
+		{[]Vec{VecXY(-1, 0), VecXY(3, 0), VecXY(0, 0), VecXY(1, 1)}, CircleXYR(1, 0, 2)},
+		{[]Vec{VecXY(0, 0), VecXY(1, 0), VecXY(3, 0)}, CircleXYR(1.5, 0, 1.5)},
+		{[]Vec{VecXY(0, 0), VecXY(4, 0), VecXY(0, 4), VecXY(4, 4), VecXY(2, 2)}, CircleXYR(2, 2, 2*math.Sqrt2)},
+		{[]Vec{VecXY(-2, 0), VecXY(2, 0), VecXY(0, 2), VecXY(0, -2), VecXY(1, 1)}, CircleXYR(0, 0, 2)},
+	}
+
+	for i, c := range cases {
+		got := EnclosingCircle(c.points)
+		if !got.Equals(c.want, 1e-9) {
+			t.Errorf("case %d: got %s, want %s", i, got, c.want)
+		}
+	}
+
+	for trial := 0; trial < 50; trial++ {
+		points := make([]Vec, rand.Intn(10)+1)
+		for i := range points {
+			points[i] = VecXY(rand.Float64()*100-50, rand.Float64()*100-50)
+		}
+		got := EnclosingCircle(points)
+		want := bruteEnclosingCircle(points)
+		if !got.Equals(want, 1e-6) {
+			t.Errorf("trial %d: got %s, want %s, points %v", trial, got, want, points)
+		}
+	}
+}
+
+// bruteEnclosingCircle finds the smallest enclosing circle by testing every circle defined
+// by 2 or 3 of the points.
+func bruteEnclosingCircle(points []Vec) Circle {
+	if len(points) == 1 {
+		return CircleVecR(points[0], 0)
+	}
+	best := Circle{R: math.Inf(1)}
+	enclosesAll := func(c Circle) bool {
+		for _, p := range points {
+			if c.Pos().Dist(p) > c.R+1e-9 {
+				return false
+			}
+		}
+		return true
+	}
+	for i := range points {
+		for j := i + 1; j < len(points); j++ {
+			if c := circleFrom2(points[i], points[j]); c.R < best.R && enclosesAll(c) {
+				best = c
+			}
+			for k := j + 1; k < len(points); k++ {
+				if c := circleFrom3(points[i], points[j], points[k]); c.R < best.R && enclosesAll(c) {
+					best = c
+				}
+			}
+		}
+	}
+	return best
+}
+
+func TestCircleUnionAll(t *testing.T) {
+	cases := []struct {
+		circles []Circle
+		want    Circle
+	}{
+		{[]Circle{}, Circle{}},
+		{[]Circle{CircleXYR(1, 2, 3)}, CircleXYR(1, 2, 3)},
+		{[]Circle{CircleXYR(1, 2, -3)}, CircleXYR(1, 2, 3)},
+		{[]Circle{CircleXYR(-2, 1, 5), CircleXYR(3, 1, 5)}, CircleXYR(0.5, 1, 7.5)},
+		{[]Circle{CircleXYR(0, 0, 5), CircleXYR(1, 1, 1)}, CircleXYR(0, 0, 5)},
+		{[]Circle{CircleXYR(1, 1, 1), CircleXYR(0, 0, 5), CircleXYR(-2, 0, 2)}, CircleXYR(0, 0, 5)},
+		{[]Circle{CircleXYR(-3, 0, 1), CircleXYR(3, 0, 1), CircleXYR(0, 3, 1), CircleXYR(0, -3, 1)}, CircleXYR(0, 0, 4)},
+		{[]Circle{CircleXYR(-3, 0, 1), CircleXYR(3, 0, 1), CircleXYR(0, 1, 1)}, CircleXYR(0, 0, 4)},
+	}
+
+	for i, c := range cases {
+		got := CircleUnion(c.circles)
+		if !got.Equals(c.want, 1e-9) {
+			t.Errorf("case %d: got %s, want %s", i, got, c.want)
+		}
+	}
+
+	for trial := 0; trial < 50; trial++ {
+		circles := make([]Circle, rand.Intn(20)+1)
+		points := make([]Vec, len(circles))
+		for i := range circles {
+			circles[i] = CircleXYR(rand.Float64()*100-50, rand.Float64()*100-50, rand.Float64()*10)
+			points[i] = circles[i].Pos()
+		}
+		got := CircleUnion(circles)
+		for _, c := range circles {
+			if got.Pos().Dist(c.Pos())+c.R > got.R+1e-6 {
+				t.Errorf("trial %d: %s does not enclose %s", trial, got, c)
+			}
+		}
+
+		// With radius 0 the Circles are points.
+		for i := range circles {
+			circles[i].R = 0
+		}
+		got = CircleUnion(circles)
+		want := EnclosingCircle(points)
+		if !got.Equals(want, 1e-6) {
+			t.Errorf("trial %d: got %s, want %s", trial, got, want)
+		}
+	}
+}
+
+func TestCircleSweepCircle(t *testing.T) {
+	type res struct {
+		t      float64
+		normal Vec
+		hit    bool
+	}
+	cases := []struct {
+		c     Circle
+		vel   Vec
+		other Circle
+		want  res
+	}{
+		{CircleXYR(0, 0, 1), VecXY(10, 0), CircleXYR(8, 0, 1), res{0.6, VecXY(-1, 0), true}},
+		{CircleXYR(0, 0, 1), VecXY(100, 0), CircleXYR(8, 0, 1), res{0.06, VecXY(-1, 0), true}},
+		{CircleXYR(0, 0, 1), VecXY(5, 0), CircleXYR(8, 0, 1), res{}},
+		{CircleXYR(0, 0, 1), VecXY(-10, 0), CircleXYR(8, 0, 1), res{}},
+		{CircleXYR(0, 0, 1), VecXY(10, 0), CircleXYR(8, 2, 1), res{}},
+		{CircleXYR(0, 0, 1), VecXY(10, 0), CircleXYR(8, 1, 1), res{(8 - math.Sqrt(3)) / 10, VecXY(-math.Sqrt(3), -1).Normalized(), true}},
+		{CircleXYR(0, 0, 1), VecXY(10, 0), CircleXYR(1, 0, 1), res{0, VecXY(-1, 0), true}},
+		{CircleXYR(0, 0, 1), VecXY(0, 0), CircleXYR(8, 0, 1), res{}},
+	}
+
+	for i, c := range cases {
+		gotT, normal, hit := c.c.SweepCircle(c.vel, c.other)
+		if hit != c.want.hit || (hit && (!fEqual(gotT, c.want.t) || !normal.Equals(c.want.normal, e))) {
+			t.Errorf("case %d: got %f, %s, %v, want %v", i, gotT, normal, hit, c.want)
+		}
+	}
+}
+
+func TestCircleSweepRect(t *testing.T) {
+	type res struct {
+		t      float64
+		normal Vec
+		hit    bool
+	}
+	wall := RectXYWH(10, -10, 1, 20)
+	cases := []struct {
+		c    Circle
+		vel  Vec
+		r    Rect
+		want res
+	}{
+		// A fast bullet that would tunnel through the wall.
+		{CircleXYR(0, 0, 1), VecXY(100, 0), wall, res{0.09, VecXY(-1, 0), true}},
+		{CircleXYR(0, 0, 1), VecXY(8, 0), wall, res{}},
+		{CircleXYR(20, 0, 1), VecXY(-20, 0), wall, res{0.4, VecXY(1, 0), true}},
+		{CircleXYR(10.5, 20, 1), VecXY(0, -20), wall, res{0.45, VecXY(0, 1), true}},
+		// Hits the rounded corner.
+		{CircleXYR(0, -10-math.Sqrt2/2, 1), VecXY(20, 0), wall,
+			res{(10 - math.Sqrt2/2) / 20, VecXY(-1, -1).Normalized(), true}},
+		// Passes by the corner where its bounding Rect would have hit.
+		{CircleXYR(-5, 15-1.2*math.Sqrt2, 1), VecXY(20, -20), RectXYWH(10, 0, 10, 10), res{}},
+		// Starts inside a corner of the Rect grown by the radius.
+		{CircleXYR(-0.8, -0.8, 1), VecXY(1, 1), RectXYWH(0, 0, 10, 10),
+			res{0.8 - math.Sqrt2/2, VecXY(-1, -1).Normalized(), true}},
+		{CircleXYR(-0.8, -0.8, 1), VecXY(0, -2), RectXYWH(0, 0, 10, 10), res{}},
+		{CircleXYR(-0.8, -0.8, 1), VecXY(-1, -1), RectXYWH(0, 0, 10, 10), res{}},
+		// Sliding along the wall.
+		{CircleXYR(9, 0, 1), VecXY(0, 5), wall, res{}},
+		// Already overlapping.
+		{CircleXYR(9.5, 0, 1), VecXY(5, 0), wall, res{0, VecXY(-1, 0), true}},
+		{CircleXYR(0, 0, 1), VecXY(100, 0), RectXYWH(11, 10, -1, -20), res{0.09, VecXY(-1, 0), true}},
+	}
+
+	for i, c := range cases {
+		gotT, normal, hit := c.c.SweepRect(c.vel, c.r)
+		if hit != c.want.hit || (hit && (!fEqual(gotT, c.want.t) || !normal.Equals(c.want.normal, e))) {
+			t.Errorf("case %d: got %f, %s, %v, want %v", i, gotT, normal, hit, c.want)
+		}
+	}
+}
+
+func TestEnclosingCircleGlobalRand(t *testing.T) {
+	points := []Vec{VecXY(0, 0), VecXY(4, 0), VecXY(1, 3), VecXY(2, -2)}
+	circles := []Circle{CircleXYR(0, 0, 1), CircleXYR(5, 1, 2), CircleXYR(2, 4, 1)}
+	rand.Seed(9)
+	want := rand.Int63()
+	rand.Seed(9)
+	EnclosingCircle(points)
+	CircleUnion(circles)
+	if got := rand.Int63(); got != want {
+		t.Errorf("global source changed: got %d, want %d", got, want)
+	}
+}
@@ -2,11 +2,15 @@
 // geared towards games.
 //
 // Includes
-//  - Types for 2-D vector, rectangle, circle, and ray
-//  - A collection of easing functions
+//  - Types for 2-D vector, rectangle, circle, convex polygon, line segment, and ray
+//  - Quadratic and cubic Bézier curves, and Catmull-Rom and B-spline paths through points
+//  - An affine transform type for moving, rotating, scaling, and shearing the other types
+//  - Spatial indexes for speeding up collision queries between many shapes
+//  - A collection of easing functions and ways to combine them, and tweens, springs, and
+//    keyframe curves for animating values
 //  - Functions for generating random numbers and vectors, including a function for randomly
 //    selecting an item from a list with weighted probabilities
-//  - Perlin noise functions
+//  - Perlin, simplex, and Worley noise functions, and fractal noise built from them
 //  - Other miscellaneous functions like Clamp, Map, and Mod
 //
 // This package assumes coordinates where +x is right and +y is down.
@@ -1,6 +1,10 @@
 package geo
 
-import "math"
+import (
+	"fmt"
+	"math"
+	"sort"
+)
 
 // Lerp linear interpolates from a to b by percent t, where t=0 returns a and
 // t=1 returns b.
@@ -180,3 +184,302 @@ func EaseInOutBounce(t float64) float64 {
 	}
 	return 0.5*EaseOutBounce(t*2-1) + 0.5
 }
+
+// EaseCubicBezier creates an EaseFn that follows a cubic Bézier curve from (0, 0) to (1, 1)
+// with the control points (x1, y1) and (x2, y2), like cubic-bezier in CSS. The curve must be
+// a function of t so x1 and x2 are clamped between 0 and 1. For t outside of 0 to 1 the
+// curve is extended along the tangent at the nearest end point.
+func EaseCubicBezier(x1, y1, x2, y2 float64) EaseFn {
+	// Based on WebKit's UnitBezier.
+	x1, x2 = Clamp(x1, 0, 1), Clamp(x2, 0, 1)
+	cx := 3 * x1
+	bx := 3*(x2-x1) - cx
+	ax := 1 - cx - bx
+	cy := 3 * y1
+	by := 3*(y2-y1) - cy
+	ay := 1 - cy - by
+	sampleX := func(s float64) float64 { return ((ax*s+bx)*s + cx) * s }
+	sampleY := func(s float64) float64 { return ((ay*s+by)*s + cy) * s }
+	sampleDX := func(s float64) float64 { return (3*ax*s+2*bx)*s + cx }
+
+	// The slopes at each end, for extending the curve.
+	startSlope := 0.0
+	if x1 > 0 {
+		startSlope = y1 / x1
+	} else if y1 == 0 && x2 > 0 {
+		startSlope = y2 / x2
+	}
+	endSlope := 0.0
+	if x2 < 1 {
+		endSlope = (y2 - 1) / (x2 - 1)
+	} else if y2 == 1 && x1 < 1 {
+		endSlope = (y1 - 1) / (x1 - 1)
+	}
+
+	const epsilon = 1e-9
+	return func(t float64) float64 {
+		if t < 0 {
+			return startSlope * t
+		}
+		if t > 1 {
+			return 1 + endSlope*(t-1)
+		}
+
+		// Newton's method is fast but may not converge, in which case fall back on bisection.
+		s := t
+		for i := 0; i < 8; i++ {
+			x := sampleX(s) - t
+			if math.Abs(x) < epsilon {
+				return sampleY(s)
+			}
+			d := sampleDX(s)
+			if math.Abs(d) < 1e-6 {
+				break
+			}
+			s -= x / d
+		}
+		lo, hi := 0.0, 1.0
+		s = t
+		for lo < hi {
+			x := sampleX(s)
+			if math.Abs(x-t) < epsilon {
+				break
+			}
+			if t > x {
+				lo = s
+			} else {
+				hi = s
+			}
+			if hi-lo < epsilon {
+				break
+			}
+			s = (lo + hi) / 2
+		}
+		return sampleY(s)
+	}
+}
+
+// StepJump determines where the jumps happen for EaseSteps.
+type StepJump int
+
+// The StepJumps, named after their equivalents in CSS.
+const (
+	// StepJumpEnd stays at each step until the end of its interval, so the last step is
+	// only reached when t is 1.
+	StepJumpEnd StepJump = iota
+	// StepJumpStart jumps to each step at the start of its interval, so the first step is
+	// reached immediately.
+	StepJumpStart
+	// StepJumpNone holds at both 0 and 1 for an interval each.
+	StepJumpNone
+	// StepJumpBoth jumps at both the start and the end, adding a step.
+	StepJumpBoth
+)
+
+// EaseSteps creates an EaseFn that moves in n equal jumps instead of smoothly, like steps in
+// CSS. n is always at least 1, or 2 for StepJumpNone.
+func EaseSteps(n int, jump StepJump) EaseFn {
+	var jumps int
+	switch jump {
+	case StepJumpNone:
+		if n < 2 {
+			n = 2
+		}
+		jumps = n - 1
+	case StepJumpBoth:
+		if n < 1 {
+			n = 1
+		}
+		jumps = n + 1
+	default:
+		if n < 1 {
+			n = 1
+		}
+		jumps = n
+	}
+	return func(t float64) float64 {
+		step := math.Floor(t * float64(n))
+		if jump == StepJumpStart || jump == StepJumpBoth {
+			step++
+		}
+		if t >= 0 && step < 0 {
+			step = 0
+		}
+		if t <= 1 && step > float64(jumps) {
+			step = float64(jumps)
+		}
+		return step / float64(jumps)
+	}
+}
+
+// EaseReverse creates an EaseFn that plays fn backwards, going from fn(1) to fn(0).
+func EaseReverse(fn EaseFn) EaseFn {
+	return func(t float64) float64 {
+		return fn(1 - t)
+	}
+}
+
+// EaseFlip creates an EaseFn that is fn rotated 180 degrees about (0.5, 0.5), which turns an
+// ease in into an ease out and vice versa.
+func EaseFlip(fn EaseFn) EaseFn {
+	return func(t float64) float64 {
+		return 1 - fn(1-t)
+	}
+}
+
+// EaseMirror creates an EaseFn that plays fn at double speed for the first half and then
+// flipped for the second half, which turns an ease in into an ease in-out, like EaseInOut.
+func EaseMirror(fn EaseFn) EaseFn {
+	return func(t float64) float64 {
+		if t < 0.5 {
+			return fn(t*2) / 2
+		}
+		return 1 - fn(2-t*2)/2
+	}
+}
+
+// EaseConcat creates an EaseFn that plays each of fns in turn. Each fn plays between two
+// consecutive split points, where splits are the times that each fn but the last ends. The
+// output of each fn is scaled so that it covers a share of 0 to 1 proportional to its weight.
+// If splits is nil then the fns get equal time, and if weights is nil then each fn covers as
+// much of the output as it does time. Otherwise there must be one less split than fns, in
+// order between 0 and 1, and the same number of weights as fns, which must not be negative
+// and must not all be 0. EaseConcat panics if fns is empty or the splits or weights are
+// invalid.
+func EaseConcat(fns []EaseFn, splits, weights []float64) EaseFn {
+	if len(fns) == 0 {
+		panic("geo: EaseConcat: no fns")
+	}
+	// starts are the times each fn starts plus 1 at the end, and outs are the outputs.
+	starts := make([]float64, len(fns)+1)
+	starts[len(fns)] = 1
+	if splits == nil {
+		for i := 1; i < len(fns); i++ {
+			starts[i] = float64(i) / float64(len(fns))
+		}
+	} else {
+		if len(splits) != len(fns)-1 {
+			panic(fmt.Sprintf("geo: EaseConcat: got %d splits for %d fns, want %d", len(splits), len(fns), len(fns)-1))
+		}
+		copy(starts[1:], splits)
+		for i := 1; i <= len(fns); i++ {
+			// Written so that NaNs fail.
+			if !(starts[i] >= starts[i-1] && starts[i] <= 1) {
+				panic(fmt.Sprintf("geo: EaseConcat: splits %v aren't in order between 0 and 1", splits))
+			}
+		}
+	}
+	outs := make([]float64, len(fns)+1)
+	if weights == nil {
+		copy(outs, starts)
+	} else {
+		if len(weights) != len(fns) {
+			panic(fmt.Sprintf("geo: EaseConcat: got %d weights for %d fns", len(weights), len(fns)))
+		}
+		total := 0.0
+		for _, w := range weights {
+			if !(w >= 0) {
+				panic(fmt.Sprintf("geo: EaseConcat: weight %f is negative or not a number", w))
+			}
+			total += w
+		}
+		if !(total > 0) || math.IsInf(total, 1) {
+			panic(fmt.Sprintf("geo: EaseConcat: weights %v must have a positive, finite total", weights))
+		}
+		for i, w := range weights {
+			outs[i+1] = outs[i] + w/total
+		}
+	}
+
+	return func(t float64) float64 {
+		i := sort.SearchFloat64s(starts[1:len(starts)-1], t)
+		if i >= len(fns) {
+			i = len(fns) - 1
+		}
+		x := 1.0
+		if span := starts[i+1] - starts[i]; span > 0 {
+			x = (t - starts[i]) / span
+		}
+		return Lerp(outs[i], outs[i+1], fns[i](x))
+	}
+}
+
+// EaseBlend creates an EaseFn that is a mix of a and b, where a weight of 0 is all a and 1
+// is all b.
+func EaseBlend(a, b EaseFn, weight float64) EaseFn {
+	return func(t float64) float64 {
+		return Lerp(a(t), b(t), weight)
+	}
+}
+
+// EaseCrossfade creates an EaseFn that starts out following a and gradually changes to
+// following b.
+func EaseCrossfade(a, b EaseFn) EaseFn {
+	return func(t float64) float64 {
+		return Lerp(a(t), b(t), t)
+	}
+}
+
+// EaseClamp creates an EaseFn that clamps both the input and output of fn between 0 and 1,
+// which removes any overshoot.
+func EaseClamp(fn EaseFn) EaseFn {
+	return func(t float64) float64 {
+		return Clamp(fn(Clamp(t, 0, 1)), 0, 1)
+	}
+}
+
+// EaseRepeat creates an EaseFn that plays fn n times, jumping back to the start each time.
+func EaseRepeat(fn EaseFn, n int) EaseFn {
+	return func(t float64) float64 {
+		x, _ := repeatTime(t, n)
+		return fn(x)
+	}
+}
+
+// EasePingPong creates an EaseFn that plays fn forward and then backward n times, ending
+// back at the start.
+func EasePingPong(fn EaseFn, n int) EaseFn {
+	return func(t float64) float64 {
+		x, i := repeatTime(t, n*2)
+		if i%2 == 1 {
+			x = 1 - x
+		}
+		return fn(x)
+	}
+}
+
+// repeatTime returns the time within the current repeat and which repeat it is, when t
+// from 0 to 1 is split into n repeats. At the end of each repeat it stays at 1 rather than
+// starting the next one.
+func repeatTime(t float64, n int) (x float64, i int) {
+	if n < 1 {
+		n = 1
+	}
+	t = Clamp(t, 0, 1) * float64(n)
+	i = int(math.Ceil(t)) - 1
+	if i < 0 {
+		return 0, 0
+	}
+	return t - float64(i), i
+}
+
+// EaseSampled creates an EaseFn that approximates fn by linearly interpolating between
+// samples+1 evenly spaced samples of it, which is faster for expensive fns like
+// EaseCubicBezier. Outside of 0 to 1 the end samples are extended.
+func EaseSampled(fn EaseFn, samples int) EaseFn {
+	if samples < 1 {
+		samples = 1
+	}
+	table := make([]float64, samples+1)
+	for i := range table {
+		table[i] = fn(float64(i) / float64(samples))
+	}
+	return func(t float64) float64 {
+		x := Clamp(t, 0, 1) * float64(samples)
+		i := int(x)
+		if i >= samples {
+			i = samples - 1
+		}
+		return Lerp(table[i], table[i+1], x-float64(i))
+	}
+}
@@ -1,6 +1,9 @@
 package geo
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestLerp(t *testing.T) {
 	cases := []struct {
@@ -38,3 +41,173 @@ func TestLerpVec(t *testing.T) {
 		}
 	}
 }
+
+func TestEaseCubicBezier(t *testing.T) {
+	cases := []struct {
+		x1, y1, x2, y2 float64
+		t, want        float64
+	}{
+		{0, 0, 1, 1, 0.3, 0.3},
+		{0.25, 0.1, 0.25, 1, 0, 0},
+		{0.25, 0.1, 0.25, 1, 1, 1},
+		{0.25, 0.1, 0.25, 1, 0.1, 0.09479630571604324},
+		{0.25, 0.1, 0.25, 1, 0.5, 0.802403387584857},
+		{0.25, 0.1, 0.25, 1, 0.9, 0.9943164774845563},
+		{0.42, 0, 0.58, 1, 0.1, 0.019722453548311196},
+		{0.42, 0, 0.58, 1, 0.5, 0.5},
+		{0.68, -0.55, 0.265, 1.55, 0.1, -0.06629147733352875},
+		{0.68, -0.55, 0.265, 1.55, 0.9, 1.06237319516637},
+		// Vertical tangents at the ends are hard for Newton's method.
+		{0, 1, 1, 0, 0.1, 0.3874003169772753},
+		{0, 1, 1, 0, 0.9, 0.6125996830227249},
+		// Outside of 0 to 1 the curve is extended along the tangents.
+		{0.5, 0.25, 0.5, 1, -1, -0.5},
+		{0.5, 0, 0.5, 0.5, 2, 2},
+		{0, 0, 0.5, 0.25, -2, -1},
+	}
+
+	for i, c := range cases {
+		got := EaseCubicBezier(c.x1, c.y1, c.x2, c.y2)(c.t)
+		if math.Abs(got-c.want) > 1e-7 {
+			t.Errorf("case %d: got %.10f, want %.10f", i, got, c.want)
+		}
+	}
+}
+
+func TestEaseSteps(t *testing.T) {
+	cases := []struct {
+		n    int
+		jump StepJump
+		t    float64
+		want float64
+	}{
+		{4, StepJumpEnd, 0, 0},
+		{4, StepJumpEnd, 0.3, 0.25},
+		{4, StepJumpEnd, 0.99, 0.75},
+		{4, StepJumpEnd, 1, 1},
+		{4, StepJumpStart, 0, 0.25},
+		{4, StepJumpStart, 0.3, 0.5},
+		{4, StepJumpStart, 1, 1},
+		{5, StepJumpNone, 0, 0},
+		{5, StepJumpNone, 0.3, 0.25},
+		{5, StepJumpNone, 0.9, 1},
+		{5, StepJumpNone, 1, 1},
+		{3, StepJumpBoth, 0, 0.25},
+		{3, StepJumpBoth, 0.5, 0.5},
+		{3, StepJumpBoth, 1, 1},
+		{0, StepJumpEnd, 0.5, 0},
+		{1, StepJumpNone, 0.6, 1},
+	}
+
+	for i, c := range cases {
+		got := EaseSteps(c.n, c.jump)(c.t)
+		if math.Abs(got-c.want) > e {
+			t.Errorf("case %d: got %f, want %f", i, got, c.want)
+		}
+	}
+}
+
+func TestEaseCombinators(t *testing.T) {
+	quad := EaseIn(2)
+	cases := []struct {
+		fn   EaseFn
+		t    float64
+		want float64
+	}{
+		{EaseReverse(quad), 0, 1},
+		{EaseReverse(quad), 0.25, 0.5625},
+		{EaseReverse(quad), 1, 0},
+		{EaseFlip(quad), 0.25, 0.4375},
+		{EaseFlip(quad), 1, 1},
+		{EaseMirror(quad), 0.25, 0.125},
+		{EaseMirror(quad), 0.5, 0.5},
+		{EaseMirror(quad), 0.75, 0.875},
+		{EaseMirror(quad), 1, 1},
+		{EaseConcat([]EaseFn{quad, EaseLinear}, nil, nil), 0.25, 0.125},
+		{EaseConcat([]EaseFn{quad, EaseLinear}, nil, nil), 0.5, 0.5},
+		{EaseConcat([]EaseFn{quad, EaseLinear}, nil, nil), 0.75, 0.75},
+		{EaseConcat([]EaseFn{quad, EaseLinear}, []float64{0.2}, nil), 0.1, 0.05},
+		{EaseConcat([]EaseFn{quad, EaseLinear}, []float64{0.2}, nil), 0.6, 0.6},
+		{EaseConcat([]EaseFn{quad, EaseLinear}, []float64{0.2}, []float64{3, 1}), 0.1, 0.1875},
+		{EaseConcat([]EaseFn{quad, EaseLinear}, []float64{0.2}, []float64{3, 1}), 0.2, 0.75},
+		{EaseConcat([]EaseFn{quad, EaseLinear}, []float64{0.2}, []float64{3, 1}), 0.6, 0.875},
+		{EaseConcat([]EaseFn{quad, EaseLinear}, []float64{0.2}, []float64{3, 1}), 1, 1},
+		{EaseConcat([]EaseFn{quad, quad, quad}, []float64{0.5, 0.5}, nil), 0.5, 0.5},
+		{EaseConcat([]EaseFn{quad, quad, quad}, []float64{0.5, 0.5}, nil), 0.75, 0.625},
+		{EaseBlend(quad, EaseLinear, 0.25), 0.5, 0.3125},
+		{EaseCrossfade(quad, EaseLinear), 0.5, 0.375},
+		{EaseCrossfade(quad, EaseLinear), 1, 1},
+		{EaseClamp(EaseOutBack), 0.8, 1},
+		{EaseClamp(EaseInBack), 0.2, 0},
+		{EaseClamp(quad), 2, 1},
+		{EaseClamp(quad), 0.5, 0.25},
+		{EaseRepeat(quad, 2), 0.25, 0.25},
+		{EaseRepeat(quad, 2), 0.5, 1},
+		{EaseRepeat(quad, 2), 0.75, 0.25},
+		{EaseRepeat(quad, 2), 1, 1},
+		{EaseRepeat(quad, 2), 0, 0},
+		{EasePingPong(quad, 1), 0.25, 0.25},
+		{EasePingPong(quad, 1), 0.5, 1},
+		{EasePingPong(quad, 1), 0.75, 0.25},
+		{EasePingPong(quad, 1), 1, 0},
+		{EasePingPong(quad, 2), 0.625, 0.25},
+		{EaseSampled(quad, 4), 0.25, 0.0625},
+		{EaseSampled(quad, 4), 0.125, 0.03125},
+		{EaseSampled(quad, 4), 1, 1},
+		{EaseSampled(quad, 4), 1.5, 1},
+	}
+
+	for i, c := range cases {
+		if got := c.fn(c.t); math.Abs(got-c.want) > e {
+			t.Errorf("case %d: got %f, want %f", i, got, c.want)
+		}
+	}
+
+	// Sampling gets closer to the original with more samples.
+	fn := EaseCubicBezier(0.25, 0.1, 0.25, 1)
+	approx := EaseSampled(fn, 200)
+	for x := 0.0; x <= 1; x += 0.01 {
+		if got, want := approx(x), fn(x); math.Abs(got-want) > 1e-4 {
+			t.Errorf("sampled %f: got %f, want %f", x, got, want)
+		}
+	}
+}
+
+func TestEaseConcatPanics(t *testing.T) {
+	two := []EaseFn{EaseLinear, EaseLinear}
+	cases := []struct {
+		fns             []EaseFn
+		splits, weights []float64
+	}{
+		{nil, nil, nil},
+		{two, []float64{}, nil},
+		{two, []float64{0.2, 0.6}, nil},
+		{[]EaseFn{EaseLinear, EaseLinear, EaseLinear}, []float64{0.6, 0.2}, nil},
+		{two, []float64{-0.1}, nil},
+		{two, []float64{1.1}, nil},
+		{two, []float64{math.NaN()}, nil},
+		{two, nil, []float64{0, 0}},
+		{two, nil, []float64{1}},
+		{two, nil, []float64{1, 2, 3}},
+		{two, nil, []float64{2, -1}},
+		{two, nil, []float64{1, math.NaN()}},
+		{two, nil, []float64{1, math.Inf(1)}},
+	}
+
+	for i, c := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("case %d: didn't panic", i)
+				}
+			}()
+			EaseConcat(c.fns, c.splits, c.weights)
+		}()
+	}
+
+	// Splits at the ends and weights of 0 are fine as long as some weight isn't.
+	fn := EaseConcat(two, []float64{0}, []float64{0, 1})
+	if got := fn(0.5); math.Abs(got-0.5) > e {
+		t.Errorf("edge: got %f, want 0.5", got)
+	}
+}
@@ -0,0 +1,105 @@
+package geo
+
+import "math"
+
+// NoiseFn is a noise function that returns values between 0 and 1, like Perlin. Functions
+// of fewer dimensions can ignore the extra arguments.
+type NoiseFn func(x, y, z float64) float64
+
+// FractalMode determines how a Fractal combines its octaves.
+type FractalMode int
+
+// The ways a Fractal can combine its octaves.
+const (
+	// FractalFBM sums the octaves as they are, which is fractional Brownian motion, like
+	// PerlinOctave.
+	FractalFBM FractalMode = iota
+	// FractalBillow sums the absolute value of each octave, centered on 0, which gives the
+	// puffy look of clouds. This is also known as turbulence.
+	FractalBillow
+	// FractalRidged inverts the absolute value of each octave to make sharp ridges, weighting
+	// each octave by the previous one so that the ridges get more detail than the valleys, as
+	// in Musgrave's ridged multifractal. Good for mountains.
+	FractalRidged
+)
+
+// Fractal combines octaves of a base NoiseFn at increasing frequency and decreasing
+// amplitude. Its At method returns values between 0 and 1 and can be used as a NoiseFn
+// itself.
+type Fractal struct {
+	// Noise is the base noise function.
+	Noise NoiseFn
+	Mode  FractalMode
+	// Octaves is the number of layers of Noise. At least 1 is always used.
+	Octaves int
+	// Frequency is the frequency of the first octave.
+	Frequency float64
+	// Lacunarity is how much the frequency is multiplied by for each octave.
+	Lacunarity float64
+	// Gain is how much the amplitude is multiplied by for each octave, which is the same as
+	// persistence in PerlinOctave.
+	Gain float64
+	// Offset is only used by FractalRidged. Larger values make the ridges wider and the
+	// valleys shallower.
+	Offset float64
+}
+
+// NewFractal creates a fBm Fractal with the given base noise and number of octaves. The
+// Frequency and Offset are 1, the Lacunarity is 2, and the Gain is 0.5.
+func NewFractal(noise NoiseFn, octaves int) *Fractal {
+	return &Fractal{
+		Noise:      noise,
+		Mode:       FractalFBM,
+		Octaves:    octaves,
+		Frequency:  1,
+		Lacunarity: 2,
+		Gain:       0.5,
+		Offset:     1,
+	}
+}
+
+// At returns the value of the Fractal at the given coordinates.
+func (f *Fractal) At(x, y, z float64) float64 {
+	total := 0.0
+	maxValue := 0.0 // Used for normalizing result to 0.0 - 1.0
+	frequency := f.Frequency
+	amplitude := 1.0
+	weight := 1.0
+	for i := 0; i < f.Octaves || i == 0; i++ {
+		// Change the noise to -1 to +1.
+		n := f.Noise(x*frequency, y*frequency, z*frequency)*2 - 1
+		switch f.Mode {
+		case FractalBillow:
+			total += math.Abs(n) * amplitude
+			maxValue += amplitude
+		case FractalRidged:
+			signal := f.Offset - math.Abs(n)
+			signal *= signal * weight
+			weight = Clamp(signal, 0, 1)
+			total += signal * amplitude
+			maxValue += f.Offset * f.Offset * amplitude
+		default:
+			total += (n + 1) / 2 * amplitude
+			maxValue += amplitude
+		}
+
+		amplitude *= f.Gain
+		frequency *= f.Lacunarity
+	}
+
+	return Clamp(total/maxValue, 0, 1)
+}
+
+// Warp returns a NoiseFn that samples noise at coordinates displaced by the warp noise,
+// which is known as domain warping. Each coordinate is moved by up to strength in either
+// direction. Warping a Fractal with another Fractal gives swirling, organic shapes.
+func Warp(noise, warp NoiseFn, strength float64) NoiseFn {
+	return func(x, y, z float64) float64 {
+		// Sample the warp noise at arbitrary offsets so that each axis is displaced
+		// differently.
+		dx := (warp(x, y, z)*2 - 1) * strength
+		dy := (warp(x+5.2, y+1.3, z+2.8)*2 - 1) * strength
+		dz := (warp(x+1.7, y+9.2, z+4.1)*2 - 1) * strength
+		return noise(x+dx, y+dy, z+dz)
+	}
+}
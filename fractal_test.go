@@ -0,0 +1,99 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFractalFBM(t *testing.T) {
+	f := NewFractal(Perlin, 4)
+	for i := 0; i < 100; i++ {
+		x, y, z := rand.Float64()*100, rand.Float64()*100, rand.Float64()*100
+		if got, want := f.At(x, y, z), PerlinOctave(x, y, z, 4, 0.5); math.Abs(got-want) > e {
+			t.Errorf("got %f, want %f", got, want)
+		}
+	}
+
+	f.Octaves = 0
+	if got, want := f.At(1.2, 3.4, 4.5), Perlin(1.2, 3.4, 4.5); got != want {
+		t.Errorf("0 octaves: got %f, want %f", got, want)
+	}
+
+	f.Octaves, f.Frequency, f.Lacunarity = 2, 0.5, 3
+	want := (Perlin(0.6, 1.7, 2.25) + Perlin(1.8, 5.1, 6.75)*0.5) / 1.5
+	if got := f.At(1.2, 3.4, 4.5); math.Abs(got-want) > e {
+		t.Errorf("frequency and lacunarity: got %f, want %f", got, want)
+	}
+}
+
+func TestFractalModes(t *testing.T) {
+	cases := []struct {
+		noise float64
+		mode  FractalMode
+		want  float64
+	}{
+		{0.5, FractalFBM, 0.5},
+		{0.5, FractalBillow, 0},
+		{0.5, FractalRidged, 1},
+		{1, FractalFBM, 1},
+		{1, FractalBillow, 1},
+		{1, FractalRidged, 0},
+		{0, FractalBillow, 1},
+		{0, FractalRidged, 0},
+		{0.75, FractalBillow, 0.5},
+		// The second octave is weighted by the first: (0.25 + 0.25*0.25*0.5) / 1.5
+		{0.75, FractalRidged, 0.1875},
+	}
+
+	for i, c := range cases {
+		noise := c.noise
+		f := NewFractal(func(x, y, z float64) float64 { return noise }, 2)
+		f.Mode = c.mode
+		if got := f.At(1, 2, 3); math.Abs(got-c.want) > e {
+			t.Errorf("case %d: got %f, want %f", i, got, c.want)
+		}
+	}
+
+	for _, mode := range []FractalMode{FractalFBM, FractalBillow, FractalRidged} {
+		f := NewFractal(Simplex3, 5)
+		f.Mode = mode
+		f.Offset = 1.2
+		for i := 0; i < 100; i++ {
+			x, y, z := rand.Float64()*100, rand.Float64()*100, rand.Float64()*100
+			if got := f.At(x, y, z); got < 0 || got > 1 {
+				t.Errorf("mode %d: out of range: got %f", mode, got)
+			}
+		}
+	}
+}
+
+func TestWarp(t *testing.T) {
+	noise := func(x, y, z float64) float64 { return x + 10*y + 100*z }
+	cases := []struct {
+		warp     float64
+		strength float64
+		want     float64
+	}{
+		{0.5, 10, 321},
+		{1, 0, 321},
+		{1, 2, 321 + 2 + 20 + 200},
+		{0, 1, 321 - 1 - 10 - 100},
+	}
+
+	for i, c := range cases {
+		warp := c.warp
+		fn := Warp(noise, func(x, y, z float64) float64 { return warp }, c.strength)
+		if got := fn(1, 2, 3); math.Abs(got-c.want) > e {
+			t.Errorf("case %d: got %f, want %f", i, got, c.want)
+		}
+	}
+
+	fn := Warp(NewFractal(Perlin, 3).At, Simplex3, 4)
+	for i := 0; i < 100; i++ {
+		x, y, z := rand.Float64()*100, rand.Float64()*100, rand.Float64()*100
+		if got := fn(x, y, z); got < 0 || got > 1 {
+			t.Errorf("out of range: got %f", got)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package geo
 
 import (
 	"math"
-	"math/rand"
 	"sort"
 	"time"
 )
@@ -27,6 +26,11 @@ func Clamp(n, a, b float64) float64 {
 // A weight of 0 will never be selected unless all are 0, in which case all indices have
 // equal probability. Negative weights are treated as 0.
 func RandIndex(weights []float64) int {
+	return defaultRand.RandIndex(weights)
+}
+
+// RandIndex is like the package level RandIndex but draws from r.
+func (r *Rand) RandIndex(weights []float64) int {
 	cumWeights := make([]float64, len(weights))
 	cumWeights[0] = weights[0]
 	for i, w := range weights {
@@ -36,10 +40,10 @@ func RandIndex(weights []float64) int {
 	}
 
 	if cumWeights[len(weights)-1] == 0.0 {
-		return rand.Intn(len(weights))
+		return r.intn(len(weights))
 	}
 
-	rnd := rand.Float64() * cumWeights[len(weights)-1]
+	rnd := r.float64() * cumWeights[len(weights)-1]
 	return sort.SearchFloat64s(cumWeights, rnd)
 }
 
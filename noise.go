@@ -1,6 +1,9 @@
 package geo
 
-import "math"
+import (
+	"math"
+	"math/rand"
+)
 
 // Implemntation from https://rosettacode.org/wiki/Perlin_noise#Go and
 // http://flafla2.github.io/2014/08/09/perlinnoise.html
@@ -25,8 +28,34 @@ var permutation = []int{
 }
 var p = append(permutation, permutation...)
 
+// Noise is a generator for the noise functions that owns its own permutation table, so that
+// different Noises give different but repeatable results. Each of the package level noise
+// functions like Perlin and Simplex2 has a corresponding method on Noise. The package level
+// functions use a fixed permutation table.
+type Noise struct {
+	// p is the permutation of 0 to 255, repeated twice to avoid wrapping indices.
+	p []int
+}
+
+// defaultNoise is used by the package level noise functions.
+var defaultNoise = &Noise{p: p}
+
+// NewNoise creates a Noise whose permutation table is shuffled using src. To get the same
+// noise every time use
+//  NewNoise(rand.NewSource(seed))
+func NewNoise(src rand.Source) *Noise {
+	perm := rand.New(src).Perm(256)
+	return &Noise{p: append(perm, perm...)}
+}
+
 // Perlin implements Perlin noise. It returns values bewtween 0 and 1.
 func Perlin(x, y, z float64) float64 {
+	return defaultNoise.Perlin(x, y, z)
+}
+
+// Perlin is like the package level Perlin but uses n's permutation.
+func (n *Noise) Perlin(x, y, z float64) float64 {
+	p := n.p
 	xi := int(math.Floor(x)) & 255
 	yi := int(math.Floor(y)) & 255
 	zi := int(math.Floor(z)) & 255
@@ -107,12 +136,108 @@ func grad(hash int, x, y, z float64) float64 {
 // contribution. A persistence of 1 means each octave has equal contribution. A persistence
 // of 0.5 means each octave contributes half as much as the previous one.
 func PerlinOctave(x, y, z float64, octaves int, persistence float64) float64 {
+	return defaultNoise.PerlinOctave(x, y, z, octaves, persistence)
+}
+
+// PerlinOctave is like the package level PerlinOctave but uses n's permutation.
+func (n *Noise) PerlinOctave(x, y, z float64, octaves int, persistence float64) float64 {
 	total := 0.0
 	frequency := 1.0
 	amplitude := 1.0
 	maxValue := 0.0 // Used for normalizing result to 0.0 - 1.0
 	for i := 0; i < octaves; i++ {
-		total += Perlin(x*frequency, y*frequency, z*frequency) * amplitude
+		total += n.Perlin(x*frequency, y*frequency, z*frequency) * amplitude
+
+		maxValue += amplitude
+
+		amplitude *= persistence
+		frequency *= 2
+	}
+
+	return total / maxValue
+}
+
+// PerlinPeriodic is like Perlin except that it repeats every px units along x, py units
+// along y, and pz units along z, like pnoise in GLSL. This is useful for seamless textures
+// and looping animations. A period of 0 or less doesn't repeat early along that axis.
+// Perlin always repeats every 256 units, so periods larger than 256 that aren't a multiple
+// of it won't tile.
+func PerlinPeriodic(x, y, z float64, px, py, pz int) float64 {
+	return defaultNoise.PerlinPeriodic(x, y, z, px, py, pz)
+}
+
+// PerlinPeriodic is like the package level PerlinPeriodic but uses n's permutation.
+func (n *Noise) PerlinPeriodic(x, y, z float64, px, py, pz int) float64 {
+	p := n.p
+	x0, x1 := periodicCell(x, px)
+	y0, y1 := periodicCell(y, py)
+	z0, z1 := periodicCell(z, pz)
+	x -= math.Floor(x)
+	y -= math.Floor(y)
+	z -= math.Floor(z)
+	u := fade(x)
+	v := fade(y)
+	w := fade(z)
+	hash := func(xi, yi, zi int) int {
+		return p[p[p[xi]+yi]+zi]
+	}
+
+	val := Lerp(
+		Lerp(
+			Lerp(
+				grad(hash(x0, y0, z0), x, y, z),
+				grad(hash(x1, y0, z0), x-1, y, z),
+				u),
+			Lerp(
+				grad(hash(x0, y1, z0), x, y-1, z),
+				grad(hash(x1, y1, z0), x-1, y-1, z),
+				u),
+			v),
+		Lerp(
+			Lerp(
+				grad(hash(x0, y0, z1), x, y, z-1),
+				grad(hash(x1, y0, z1), x-1, y, z-1),
+				u),
+			Lerp(
+				grad(hash(x0, y1, z1), x, y-1, z-1),
+				grad(hash(x1, y1, z1), x-1, y-1, z-1),
+				u),
+			v),
+		w)
+
+	return (val + 1) / 2
+}
+
+// periodicCell returns the lattice coordinates, wrapped to the period, on either side of x.
+func periodicCell(x float64, period int) (i0, i1 int) {
+	i := int(math.Floor(x))
+	if period <= 0 {
+		return i & 255, (i + 1) & 255
+	}
+	i %= period
+	if i < 0 {
+		i += period
+	}
+	return i & 255, (i + 1) % period & 255
+}
+
+// PerlinPeriodicOctave is like PerlinOctave but uses PerlinPeriodic. The periods are scaled
+// along with the frequency of each octave so that the result still repeats every px, py,
+// and pz units.
+func PerlinPeriodicOctave(x, y, z float64, px, py, pz, octaves int, persistence float64) float64 {
+	return defaultNoise.PerlinPeriodicOctave(x, y, z, px, py, pz, octaves, persistence)
+}
+
+// PerlinPeriodicOctave is like the package level PerlinPeriodicOctave but uses n's
+// permutation.
+func (n *Noise) PerlinPeriodicOctave(x, y, z float64, px, py, pz, octaves int, persistence float64) float64 {
+	total := 0.0
+	frequency := 1
+	amplitude := 1.0
+	maxValue := 0.0
+	for i := 0; i < octaves; i++ {
+		f := float64(frequency)
+		total += n.PerlinPeriodic(x*f, y*f, z*f, px*frequency, py*frequency, pz*frequency) * amplitude
 
 		maxValue += amplitude
 
@@ -122,3 +247,114 @@ func PerlinOctave(x, y, z float64, octaves int, persistence float64) float64 {
 
 	return total / maxValue
 }
+
+// PerlinDeriv is like Perlin but also returns the partial derivatives of the noise along
+// each axis, which are exact, unlike finite differences. They can be used for normal maps
+// or flow fields.
+func PerlinDeriv(x, y, z float64) (val, dx, dy, dz float64) {
+	return defaultNoise.PerlinDeriv(x, y, z)
+}
+
+// PerlinDeriv is like the package level PerlinDeriv but uses n's permutation.
+func (n *Noise) PerlinDeriv(x, y, z float64) (val, dx, dy, dz float64) {
+	p := n.p
+	xi := int(math.Floor(x)) & 255
+	yi := int(math.Floor(y)) & 255
+	zi := int(math.Floor(z)) & 255
+	x -= math.Floor(x)
+	y -= math.Floor(y)
+	z -= math.Floor(z)
+	u, du := fade(x), fadeDeriv(x)
+	v, dv := fade(y), fadeDeriv(y)
+	w, dw := fade(z), fadeDeriv(z)
+	a := p[xi] + yi
+	aa := p[a] + zi
+	ab := p[a+1] + zi
+	b := p[xi+1] + yi
+	ba := p[b] + zi
+	bb := p[b+1] + zi
+
+	// The gradient and its dot product with the offset at each corner.
+	g000, g100, g010, g110 := gradVec(p[aa]), gradVec(p[ba]), gradVec(p[ab]), gradVec(p[bb])
+	g001, g101, g011, g111 := gradVec(p[aa+1]), gradVec(p[ba+1]), gradVec(p[ab+1]), gradVec(p[bb+1])
+	n000 := g000[0]*x + g000[1]*y + g000[2]*z
+	n100 := g100[0]*(x-1) + g100[1]*y + g100[2]*z
+	n010 := g010[0]*x + g010[1]*(y-1) + g010[2]*z
+	n110 := g110[0]*(x-1) + g110[1]*(y-1) + g110[2]*z
+	n001 := g001[0]*x + g001[1]*y + g001[2]*(z-1)
+	n101 := g101[0]*(x-1) + g101[1]*y + g101[2]*(z-1)
+	n011 := g011[0]*x + g011[1]*(y-1) + g011[2]*(z-1)
+	n111 := g111[0]*(x-1) + g111[1]*(y-1) + g111[2]*(z-1)
+
+	// Expand the trilinear interpolation into a polynomial of u, v, and w so that it can be
+	// differentiated.
+	k1 := n100 - n000
+	k2 := n010 - n000
+	k3 := n001 - n000
+	k4 := n000 - n100 - n010 + n110
+	k5 := n000 - n010 - n001 + n011
+	k6 := n000 - n100 - n001 + n101
+	k7 := -n000 + n100 + n010 - n110 + n001 - n101 - n011 + n111
+	val = n000 + k1*u + k2*v + k3*w + k4*u*v + k5*v*w + k6*w*u + k7*u*v*w
+
+	// The gradients themselves also contribute since each dot product changes with position.
+	lerpGrad := func(i int) float64 {
+		return Lerp(
+			Lerp(Lerp(g000[i], g100[i], u), Lerp(g010[i], g110[i], u), v),
+			Lerp(Lerp(g001[i], g101[i], u), Lerp(g011[i], g111[i], u), v),
+			w)
+	}
+	dx = lerpGrad(0) + du*(k1+k4*v+k6*w+k7*v*w)
+	dy = lerpGrad(1) + dv*(k2+k4*u+k5*w+k7*u*w)
+	dz = lerpGrad(2) + dw*(k3+k5*v+k6*u+k7*u*v)
+
+	// Change to 0 to +1 like Perlin, which halves the derivatives.
+	return (val + 1) / 2, dx / 2, dy / 2, dz / 2
+}
+
+func fadeDeriv(t float64) float64 {
+	return 30 * t * t * (t*(t-2) + 1)
+}
+
+// gradVec returns the gradient vector that grad uses for hash.
+func gradVec(hash int) [3]float64 {
+	switch hash & 15 {
+	case 0, 12:
+		return [3]float64{1, 1, 0}
+	case 1, 14:
+		return [3]float64{-1, 1, 0}
+	case 2:
+		return [3]float64{1, -1, 0}
+	case 3:
+		return [3]float64{-1, -1, 0}
+	case 4:
+		return [3]float64{1, 0, 1}
+	case 5:
+		return [3]float64{-1, 0, 1}
+	case 6:
+		return [3]float64{1, 0, -1}
+	case 7:
+		return [3]float64{-1, 0, -1}
+	case 8:
+		return [3]float64{0, 1, 1}
+	case 9, 13:
+		return [3]float64{0, -1, 1}
+	case 10:
+		return [3]float64{0, 1, -1}
+	}
+	return [3]float64{0, -1, -1}
+}
+
+// PerlinCurl returns the curl of Perlin noise in the xy plane at the given coordinates,
+// which is the gradient rotated by 90 degrees. The resulting field has no sources or sinks,
+// so particles following it swirl around without clumping together. Changing z over time
+// animates the field.
+func PerlinCurl(x, y, z float64) Vec {
+	return defaultNoise.PerlinCurl(x, y, z)
+}
+
+// PerlinCurl is like the package level PerlinCurl but uses n's permutation.
+func (n *Noise) PerlinCurl(x, y, z float64) Vec {
+	_, dx, dy, _ := n.PerlinDeriv(x, y, z)
+	return Vec{X: dy, Y: -dx}
+}
@@ -49,3 +49,122 @@ func TestPerlinOctave(t *testing.T) {
 		t.Errorf("got %f, want %f", got, (a+b)/2)
 	}
 }
+
+func TestNoiseSeeded(t *testing.T) {
+	fns := func(n *Noise) []func(x, y, z float64) float64 {
+		return []func(x, y, z float64) float64{
+			n.Perlin,
+			func(x, y, z float64) float64 { return n.PerlinOctave(x, y, z, 3, 0.5) },
+			func(x, y, z float64) float64 { return n.Simplex2(x, y) },
+			n.Simplex3,
+			func(x, y, z float64) float64 { return n.Simplex4(x, y, z, x+y) },
+		}
+	}
+	same1 := fns(NewNoise(rand.NewSource(1)))
+	same2 := fns(NewNoise(rand.NewSource(1)))
+	other := fns(NewNoise(rand.NewSource(2)))
+	for i := range same1 {
+		differs := false
+		for j := 0; j < 100; j++ {
+			x, y, z := rand.Float64()*100, rand.Float64()*100, rand.Float64()*100
+			got1, got2 := same1[i](x, y, z), same2[i](x, y, z)
+			if got1 != got2 {
+				t.Errorf("fn %d: got %f and %f from the same seed", i, got1, got2)
+			}
+			if got1 < 0 || got1 > 1 {
+				t.Errorf("fn %d: out of range: got %f", i, got1)
+			}
+			if other[i](x, y, z) != got1 {
+				differs = true
+			}
+		}
+		if !differs {
+			t.Errorf("fn %d: different seeds gave the same noise", i)
+		}
+	}
+}
+
+func TestPerlinPeriodic(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		x, y, z := rand.Float64()*1000-500, rand.Float64()*1000-500, rand.Float64()*1000-500
+		// Perlin already repeats every 256.
+		want := Perlin(x, y, z)
+		if got := PerlinPeriodic(x, y, z, 256, 256, 256); got != want {
+			t.Errorf("period 256: got %f, want %f", got, want)
+		}
+		if got := PerlinPeriodic(x, y, z, 0, 0, -1); got != want {
+			t.Errorf("no period: got %f, want %f", got, want)
+		}
+
+		px, py, pz := rand.Intn(20)+1, rand.Intn(20)+1, rand.Intn(20)+1
+		want = PerlinPeriodic(x, y, z, px, py, pz)
+		if want < 0 || want > 1 {
+			t.Errorf("out of range: got %f", want)
+		}
+		shifts := [][3]int{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {-2, 3, -1}}
+		for _, s := range shifts {
+			sx, sy, sz := float64(s[0]*px), float64(s[1]*py), float64(s[2]*pz)
+			got := PerlinPeriodic(x+sx, y+sy, z+sz, px, py, pz)
+			if math.Abs(got-want) > 1e-9 {
+				t.Errorf("period %d, %d, %d: shift %v: got %f, want %f", px, py, pz, s, got, want)
+			}
+		}
+	}
+}
+
+func TestPerlinPeriodicOctave(t *testing.T) {
+	n := NewNoise(rand.NewSource(3))
+	for i := 0; i < 100; i++ {
+		x, y, z := rand.Float64()*100, rand.Float64()*100, rand.Float64()*100
+		if got, want := n.PerlinPeriodicOctave(x, y, z, 0, 0, 0, 4, 0.5),
+			n.PerlinOctave(x, y, z, 4, 0.5); math.Abs(got-want) > e {
+			t.Errorf("no period: got %f, want %f", got, want)
+		}
+
+		want := n.PerlinPeriodicOctave(x, y, z, 8, 4, 16, 4, 0.5)
+		if want < 0 || want > 1 {
+			t.Errorf("out of range: got %f", want)
+		}
+		if got := n.PerlinPeriodicOctave(x+8, y-4, z+16, 8, 4, 16, 4, 0.5); math.Abs(got-want) > 1e-9 {
+			t.Errorf("shifted: got %f, want %f", got, want)
+		}
+	}
+}
+
+func TestPerlinDeriv(t *testing.T) {
+	const h = 1e-6
+	for i := 0; i < 200; i++ {
+		x, y, z := rand.Float64()*100-50, rand.Float64()*100-50, rand.Float64()*100-50
+		val, dx, dy, dz := PerlinDeriv(x, y, z)
+		if want := Perlin(x, y, z); math.Abs(val-want) > e {
+			t.Errorf("value at %f, %f, %f: got %f, want %f", x, y, z, val, want)
+		}
+		wantDx := (Perlin(x+h, y, z) - Perlin(x-h, y, z)) / (2 * h)
+		wantDy := (Perlin(x, y+h, z) - Perlin(x, y-h, z)) / (2 * h)
+		wantDz := (Perlin(x, y, z+h) - Perlin(x, y, z-h)) / (2 * h)
+		if math.Abs(dx-wantDx) > 1e-4 || math.Abs(dy-wantDy) > 1e-4 || math.Abs(dz-wantDz) > 1e-4 {
+			t.Errorf("derivatives at %f, %f, %f: got %f, %f, %f, want %f, %f, %f",
+				x, y, z, dx, dy, dz, wantDx, wantDy, wantDz)
+		}
+	}
+}
+
+func TestPerlinCurl(t *testing.T) {
+	const h = 1e-5
+	n := NewNoise(rand.NewSource(9))
+	for i := 0; i < 100; i++ {
+		x, y, z := rand.Float64()*100, rand.Float64()*100, rand.Float64()*100
+		got := n.PerlinCurl(x, y, z)
+		_, dx, dy, _ := n.PerlinDeriv(x, y, z)
+		if want := VecXY(dy, -dx); !got.Equals(want, e) {
+			t.Errorf("got %s, want %s", got, want)
+		}
+
+		// The field is divergence free.
+		div := (n.PerlinCurl(x+h, y, z).X-n.PerlinCurl(x-h, y, z).X)/(2*h) +
+			(n.PerlinCurl(x, y+h, z).Y-n.PerlinCurl(x, y-h, z).Y)/(2*h)
+		if math.Abs(div) > 1e-4 {
+			t.Errorf("divergence at %f, %f, %f: got %f, want 0", x, y, z, div)
+		}
+	}
+}
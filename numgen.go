@@ -5,6 +5,38 @@ import (
 	"math/rand"
 )
 
+// Rand is a source of random numbers for the random generator functions. Each of the
+// package level functions like RandNum and RandVec has a corresponding method on Rand so
+// that generators can be driven by a seeded stream, e.g. for replays or deterministic tests.
+// A nil *Rand, which the package level functions use, draws from the default source in
+// math/rand. A Rand is not safe for concurrent use by multiple goroutines.
+type Rand struct {
+	r *rand.Rand
+}
+
+// defaultRand is used by the package level random functions.
+var defaultRand *Rand
+
+// NewRand creates a Rand that draws from src. To get a deterministic sequence use
+//  NewRand(rand.NewSource(seed))
+func NewRand(src rand.Source) *Rand {
+	return &Rand{r: rand.New(src)}
+}
+
+func (r *Rand) float64() float64 {
+	if r == nil || r.r == nil {
+		return rand.Float64()
+	}
+	return r.r.Float64()
+}
+
+func (r *Rand) intn(n int) int {
+	if r == nil || r.r == nil {
+		return rand.Intn(n)
+	}
+	return r.r.Intn(n)
+}
+
 // NumGen (Number Generator) is a function that returns a number.
 type NumGen func() float64
 
@@ -17,12 +49,17 @@ func ConstNum(n float64) NumGen {
 
 // RandNum returns a NumGen that returns a uniform random number in [min(a, b), max(a, b)).
 func RandNum(a, b float64) NumGen {
+	return defaultRand.RandNum(a, b)
+}
+
+// RandNum is like the package level RandNum but draws from r.
+func (r *Rand) RandNum(a, b float64) NumGen {
 	if a > b {
 		a, b = b, a
 	}
 	width := b - a
 	return func() float64 {
-		return rand.Float64()*width + a
+		return r.float64()*width + a
 	}
 }
 
@@ -30,6 +67,11 @@ func RandNum(a, b float64) NumGen {
 // [min(radius1, radius2), max(radius1, radius2)). This function is undefined for negative
 // radii.
 func RandRadius(radius1, radius2 float64) NumGen {
+	return defaultRand.RandRadius(radius1, radius2)
+}
+
+// RandRadius is like the package level RandRadius but draws from r.
+func (r *Rand) RandRadius(radius1, radius2 float64) NumGen {
 	if radius1 > radius2 {
 		radius1, radius2 = radius2, radius1
 	}
@@ -41,7 +83,7 @@ func RandRadius(radius1, radius2 float64) NumGen {
 	unitMin := radius1 / radius2
 	unitMin *= unitMin
 	return func() float64 {
-		return math.Sqrt(rand.Float64()*(1-unitMin)+unitMin) * radius2
+		return math.Sqrt(r.float64()*(1-unitMin)+unitMin) * radius2
 	}
 }
 
@@ -76,11 +118,16 @@ func OffsetVec(gen VecGen, offset VecGen) VecGen {
 // in [min(radius1, radius2), max(radius1, radius2)), and is uniformly distributed within
 // the circle.
 func RandVecCircle(radius1, radius2 float64) VecGen {
+	return defaultRand.RandVecCircle(radius1, radius2)
+}
+
+// RandVecCircle is like the package level RandVecCircle but draws from r.
+func (r *Rand) RandVecCircle(radius1, radius2 float64) VecGen {
 	if radius1 > radius2 {
 		radius1, radius2 = radius2, radius1
 	}
 	return func() Vec {
-		return RandVec().Times(circleRadius(radius1, radius2))
+		return r.RandVec().Times(r.circleRadius(radius1, radius2))
 	}
 }
 
@@ -89,6 +136,11 @@ func RandVecCircle(radius1, radius2 float64) VecGen {
 // of the vector will be within [min(radius1, radius2), max(radius1, radius2)) and the
 // angle will be within [min(radians1, radians2), max(radians1, radians2)).
 func RandVecArc(radius1, radius2, radians1, radians2 float64) VecGen {
+	return defaultRand.RandVecArc(radius1, radius2, radians1, radians2)
+}
+
+// RandVecArc is like the package level RandVecArc but draws from r.
+func (r *Rand) RandVecArc(radius1, radius2, radians1, radians2 float64) VecGen {
 	if radius1 > radius2 {
 		radius1, radius2 = radius2, radius1
 	}
@@ -96,18 +148,23 @@ func RandVecArc(radius1, radius2, radians1, radians2 float64) VecGen {
 		radians1, radians2 = radians2, radians1
 	}
 	return func() Vec {
-		r := circleRadius(radius1, radius2)
-		rad := rand.Float64()*(radians2-radians1) + radians1
-		return Vec{X: r}.Rotated(rad)
+		radius := r.circleRadius(radius1, radius2)
+		rad := r.float64()*(radians2-radians1) + radians1
+		return Vec{X: radius}.Rotated(rad)
 	}
 }
 
 // RandVecRect returns a VecGen that will generate a random vector within the given Rect.
 func RandVecRect(rect Rect) VecGen {
+	return defaultRand.RandVecRect(rect)
+}
+
+// RandVecRect is like the package level RandVecRect but draws from r.
+func (r *Rand) RandVecRect(rect Rect) VecGen {
 	return func() Vec {
 		return Vec{
-			X: rand.Float64()*rect.W + rect.X,
-			Y: rand.Float64()*rect.H + rect.Y,
+			X: r.float64()*rect.W + rect.X,
+			Y: r.float64()*rect.H + rect.Y,
 		}
 	}
 }
@@ -116,6 +173,11 @@ func RandVecRect(rect Rect) VecGen {
 // distributed between all the given rects. If the slice given is empty then the zero
 // vector is returned.
 func RandVecRects(rects []Rect) VecGen {
+	return defaultRand.RandVecRects(rects)
+}
+
+// RandVecRects is like the package level RandVecRects but draws from r.
+func (r *Rand) RandVecRects(rects []Rect) VecGen {
 	if len(rects) == 0 {
 		return func() Vec { return Vec{} }
 	}
@@ -124,20 +186,20 @@ func RandVecRects(rects []Rect) VecGen {
 		areas[i] = rects[i].Area()
 	}
 	return func() Vec {
-		rect := rects[RandIndex(areas)]
+		rect := rects[r.RandIndex(areas)]
 		return Vec{
-			X: rand.Float64()*rect.W + rect.X,
-			Y: rand.Float64()*rect.H + rect.Y,
+			X: r.float64()*rect.W + rect.X,
+			Y: r.float64()*rect.H + rect.Y,
 		}
 	}
 }
 
 // Returns a uniformaly distributed radius between minR and maxR.
-func circleRadius(minR, maxR float64) float64 {
+func (r *Rand) circleRadius(minR, maxR float64) float64 {
 	if maxR == minR {
 		return maxR
 	}
 	unitMin := minR / maxR
 	unitMin *= unitMin
-	return math.Sqrt(rand.Float64()*(1-unitMin)+unitMin) * maxR
+	return math.Sqrt(r.float64()*(1-unitMin)+unitMin) * maxR
 }
@@ -223,3 +223,50 @@ func TestRandVecRects(t *testing.T) {
 		}
 	}
 }
+
+func TestRandSeeded(t *testing.T) {
+	trials := 100
+	rects := []Rect{RectXYWH(0, 0, 1, 1), RectXYWH(5, 5, 2, 2)}
+	weights := []float64{1, 2, 3}
+	gens := func(r *Rand) []func() float64 {
+		circle := r.RandVecCircle(1, 5)
+		arc := r.RandVecArc(1, 5, 0, math.Pi)
+		rect := r.RandVecRect(rects[0])
+		multiRect := r.RandVecRects(rects)
+		return []func() float64{
+			r.RandNum(-5, 5),
+			r.RandRadius(1, 5),
+			func() float64 { return circle().X },
+			func() float64 { return arc().Y },
+			func() float64 { return rect().X },
+			func() float64 { return multiRect().Y },
+			func() float64 { return r.RandVec().X },
+			func() float64 { return float64(r.RandIndex(weights)) },
+		}
+	}
+
+	seed := rand.Int63()
+	gens1 := gens(NewRand(rand.NewSource(seed)))
+	gens2 := gens(NewRand(rand.NewSource(seed)))
+	for i := range gens1 {
+		for j := 0; j < trials; j++ {
+			got1, got2 := gens1[i](), gens2[i]()
+			if got1 != got2 {
+				t.Errorf("gen %d: trial %d: got %f and %f from the same seed", i, j, got1, got2)
+			}
+		}
+	}
+}
+
+func TestRandNil(t *testing.T) {
+	var r *Rand
+	gen := r.RandNum(1, 2)
+	for i := 0; i < 100; i++ {
+		if got := gen(); !isBetween(got, 1, 2) {
+			t.Errorf("trial %d: got %f, want between %f and %f", i, got, 1.0, 2.0)
+		}
+	}
+	if got := r.RandVec().Len(); math.Abs(got-1) > e {
+		t.Errorf("got len %f, want %f", got, 1.0)
+	}
+}
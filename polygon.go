@@ -0,0 +1,366 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Polygon is a 2-D convex polygon defined by its vertices. The vertices may be listed in
+// either winding order but must form a convex shape, otherwise the results of the collision
+// functions are undefined. Polygons with fewer than 3 vertices have no area and never
+// collide with anything.
+type Polygon []Vec
+
+func (p Polygon) String() string {
+	vs := make([]string, len(p))
+	for i, v := range p {
+		vs[i] = fmt.Sprintf("(%g, %g)", v.X, v.Y)
+	}
+	return fmt.Sprintf("Polygon(%s)", strings.Join(vs, ", "))
+}
+
+// PolygonRect creates a Polygon with the same corners as the Rect.
+func PolygonRect(r Rect) Polygon {
+	return Polygon{
+		VecXY(r.TopLeft()),
+		VecXY(r.TopRight()),
+		VecXY(r.BottomRight()),
+		VecXY(r.BottomLeft()),
+	}
+}
+
+// Equals returns true if both Polygons have the same number of vertices and the corresponding
+// vertices are within the error e.
+func (p Polygon) Equals(other Polygon, e float64) bool {
+	if len(p) != len(other) {
+		return false
+	}
+	for i, v := range p {
+		if !v.Equals(other[i], e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Area returns the area of the Polygon.
+func (p Polygon) Area() float64 {
+	return math.Abs(p.signedArea())
+}
+
+// signedArea returns the area of the Polygon using the shoelace formula. The sign depends
+// on the winding order of the vertices.
+func (p Polygon) signedArea() float64 {
+	if len(p) < 3 {
+		return 0
+	}
+	a := 0.0
+	for i, v := range p {
+		a += v.Cross(p[(i+1)%len(p)])
+	}
+	return a / 2
+}
+
+// Centroid returns the center of mass of the Polygon. If the Polygon has no area then the
+// average of its vertices is returned, and an empty Polygon returns the zero vector.
+func (p Polygon) Centroid() Vec {
+	if len(p) == 0 {
+		return Vec{}
+	}
+	a := p.signedArea()
+	if a == 0 {
+		sum := Vec{}
+		for _, v := range p {
+			sum.Add(v)
+		}
+		return sum.DividedBy(float64(len(p)))
+	}
+	c := Vec{}
+	for i, v1 := range p {
+		v2 := p[(i+1)%len(p)]
+		cross := v1.Cross(v2)
+		c.X += (v1.X + v2.X) * cross
+		c.Y += (v1.Y + v2.Y) * cross
+	}
+	return c.DividedBy(6 * a)
+}
+
+// BoundingRect returns the smallest Rect that surrounds the Polygon. An empty Polygon
+// returns a Rect with size 0.
+func (p Polygon) BoundingRect() Rect {
+	if len(p) == 0 {
+		return Rect{}
+	}
+	left, top := p[0].X, p[0].Y
+	right, bottom := left, top
+	for _, v := range p {
+		left = math.Min(left, v.X)
+		top = math.Min(top, v.Y)
+		right = math.Max(right, v.X)
+		bottom = math.Max(bottom, v.Y)
+	}
+	return RectCorners(left, top, right, bottom)
+}
+
+// Move moves every vertex of the Polygon by the given offset, in place.
+func (p Polygon) Move(dx, dy float64) {
+	for i := range p {
+		p[i].X += dx
+		p[i].Y += dy
+	}
+}
+
+// Moved returns a new Polygon moved by the given offset relative to this one.
+func (p Polygon) Moved(dx, dy float64) Polygon {
+	moved := make(Polygon, len(p))
+	copy(moved, p)
+	moved.Move(dx, dy)
+	return moved
+}
+
+// CollidePoint returns true if the point is within the Polygon. A point along an edge is
+// not considered inside.
+func (p Polygon) CollidePoint(x, y float64) bool {
+	if len(p) < 3 {
+		return false
+	}
+	pt := VecXY(x, y)
+	sign := 0.0
+	for i, v1 := range p {
+		v2 := p[(i+1)%len(p)]
+		if v2 == v1 {
+			// Repeated vertices don't make an edge.
+			continue
+		}
+		cross := v2.Minus(v1).Cross(pt.Minus(v1))
+		if cross == 0 || cross*sign < 0 {
+			return false
+		}
+		sign = cross
+	}
+	return true
+}
+
+// CollidePolygon returns true if the Polygons overlap.
+func (p Polygon) CollidePolygon(other Polygon) bool {
+	if len(p) < 3 || len(other) < 3 {
+		return false
+	}
+	// Separating axis theorem: the Polygons overlap only if their projections overlap on
+	// every edge normal of both.
+	return !p.separatedBy(p, other) && !p.separatedBy(other, other)
+}
+
+// separatedBy returns true if one of the edge normals of axes separates p and other.
+func (p Polygon) separatedBy(axes, other Polygon) bool {
+	for _, axis := range axes.edgeNormals() {
+		// Repeated vertices make edges with no direction, which can't separate anything.
+		if axis.Len2() == 0 {
+			continue
+		}
+		min1, max1 := p.project(axis)
+		min2, max2 := other.project(axis)
+		if max1 <= min2 || max2 <= min1 {
+			return true
+		}
+	}
+	return false
+}
+
+// edgeNormals returns a vector perpendicular to each edge of the Polygon. The returned
+// vectors are not normalized.
+func (p Polygon) edgeNormals() []Vec {
+	normals := make([]Vec, len(p))
+	for i, v1 := range p {
+		edge := p[(i+1)%len(p)].Minus(v1)
+		normals[i] = VecXY(-edge.Y, edge.X)
+	}
+	return normals
+}
+
+// project returns the range covered by the Polygon when projected onto axis.
+func (p Polygon) project(axis Vec) (min, max float64) {
+	min = math.Inf(1)
+	max = math.Inf(-1)
+	for _, v := range p {
+		d := v.Dot(axis)
+		min = math.Min(min, d)
+		max = math.Max(max, d)
+	}
+	return
+}
+
+// CollidePolygonList returns the index of the first Polygon this one collides with. If
+// there is no collision then ok is false and i is undefined.
+func (p Polygon) CollidePolygonList(others []Polygon) (i int, ok bool) {
+	for i, other := range others {
+		if p.CollidePolygon(other) {
+			return i, true
+		}
+	}
+	return
+}
+
+// CollidePolygonListAll returns a list of indices of the Polygons that collide with this
+// one, or an empty list if none.
+func (p Polygon) CollidePolygonListAll(others []Polygon) []int {
+	list := make([]int, 0, len(others))
+	for i, other := range others {
+		if p.CollidePolygon(other) {
+			list = append(list, i)
+		}
+	}
+	return list
+}
+
+// CollideRect returns true if the Polygon is colliding with the Rect.
+func (p Polygon) CollideRect(r Rect) bool {
+	return p.CollidePolygon(PolygonRect(r.Normalized()))
+}
+
+// CollideRectList returns the index of the first Rect the Polygon collides with. If there
+// is no collision then ok is false and i is undefined.
+func (p Polygon) CollideRectList(rs []Rect) (i int, ok bool) {
+	for i, r := range rs {
+		if p.CollideRect(r) {
+			return i, true
+		}
+	}
+	return
+}
+
+// CollideRectListAll returns a list of indices of the Rects that collide with the Polygon,
+// or an empty list if none.
+func (p Polygon) CollideRectListAll(rs []Rect) []int {
+	list := make([]int, 0, len(rs))
+	for i, r := range rs {
+		if p.CollideRect(r) {
+			list = append(list, i)
+		}
+	}
+	return list
+}
+
+// CollideCircle returns true if the Polygon is colliding with the Circle.
+func (p Polygon) CollideCircle(c Circle) bool {
+	if len(p) < 3 {
+		return false
+	}
+	center := c.Pos()
+	for _, axis := range p.circleAxes(c) {
+		if axis.Len2() == 0 {
+			continue
+		}
+		axis.Normalize()
+		min, max := p.project(axis)
+		d := center.Dot(axis)
+		if max <= d-c.R || d+c.R <= min {
+			return false
+		}
+	}
+	return true
+}
+
+// circleAxes returns the axes to test for separation between the Polygon and Circle, which
+// are the edge normals of the Polygon plus the axis from the vertex closest to the Circle's
+// center.
+func (p Polygon) circleAxes(c Circle) []Vec {
+	center := c.Pos()
+	closest := p[0]
+	for _, v := range p {
+		if v.Dist2(center) < closest.Dist2(center) {
+			closest = v
+		}
+	}
+	return append(p.edgeNormals(), center.Minus(closest))
+}
+
+// CollideCircleList returns the index of the first Circle the Polygon collides with. If
+// there is no collision then ok is false and i is undefined.
+func (p Polygon) CollideCircleList(cs []Circle) (i int, ok bool) {
+	for i, c := range cs {
+		if p.CollideCircle(c) {
+			return i, true
+		}
+	}
+	return
+}
+
+// CollideCircleListAll returns a list of indices of the Circles that collide with the
+// Polygon, or an empty list if none.
+func (p Polygon) CollideCircleListAll(cs []Circle) []int {
+	list := make([]int, 0, len(cs))
+	for i, c := range cs {
+		if p.CollideCircle(c) {
+			list = append(list, i)
+		}
+	}
+	return list
+}
+
+// PenetrationPolygon returns the minimum translation vector (MTV) that would move this
+// Polygon out of other, along with the unit contact normal pointing from other towards this
+// Polygon. If the Polygons don't collide then hit is false and mtv and normal are the zero
+// vector.
+func (p Polygon) PenetrationPolygon(other Polygon) (mtv, normal Vec, hit bool) {
+	if len(p) < 3 || len(other) < 3 {
+		return
+	}
+	axes := append(p.edgeNormals(), other.edgeNormals()...)
+	return penetration(axes, p.project, other.project)
+}
+
+// PenetrationRect returns the minimum translation vector (MTV) that would move the Polygon
+// out of the Rect, along with the unit contact normal pointing from the Rect towards the
+// Polygon. If they don't collide then hit is false and mtv and normal are the zero vector.
+func (p Polygon) PenetrationRect(r Rect) (mtv, normal Vec, hit bool) {
+	return p.PenetrationPolygon(PolygonRect(r.Normalized()))
+}
+
+// PenetrationCircle returns the minimum translation vector (MTV) that would move the
+// Polygon out of the Circle, along with the unit contact normal pointing from the Circle
+// towards the Polygon. If they don't collide then hit is false and mtv and normal are the
+// zero vector.
+func (p Polygon) PenetrationCircle(c Circle) (mtv, normal Vec, hit bool) {
+	if len(p) < 3 {
+		return
+	}
+	center := c.Pos()
+	projectCircle := func(axis Vec) (min, max float64) {
+		d := center.Dot(axis)
+		return d - c.R, d + c.R
+	}
+	return penetration(p.circleAxes(c), p.project, projectCircle)
+}
+
+// penetration uses the separating axis theorem to find the smallest translation along one
+// of the axes that separates the ranges given by project1 and project2. The translation
+// moves the first shape away from the second. The project functions are always given
+// normalized axes.
+func penetration(axes []Vec, project1, project2 func(axis Vec) (min, max float64)) (mtv, normal Vec, hit bool) {
+	depth := math.Inf(1)
+	for _, axis := range axes {
+		if axis.Len2() == 0 {
+			continue
+		}
+		axis.Normalize()
+		min1, max1 := project1(axis)
+		min2, max2 := project2(axis)
+		if max1 <= min2 || max2 <= min1 {
+			return Vec{}, Vec{}, false
+		}
+		// Pushing forward along the axis moves min1 to max2 while pushing backward moves
+		// max1 to min2, so containment is resolved along the shorter way out.
+		if d := max2 - min1; d < depth {
+			depth, normal = d, axis
+		}
+		if d := max1 - min2; d < depth {
+			depth, normal = d, axis.Times(-1)
+		}
+	}
+	if math.IsInf(depth, 1) {
+		return Vec{}, Vec{}, false
+	}
+	return normal.Times(depth), normal, true
+}
@@ -0,0 +1,360 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPolygonString(t *testing.T) {
+	p := Polygon{VecXY(1.2, -3.4), VecXY(5, 6), VecXY(0, 7.8)}
+	got := p.String()
+	want := "Polygon((1.2, -3.4), (5, 6), (0, 7.8))"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestPolygonRect(t *testing.T) {
+	got := PolygonRect(RectXYWH(1, 2, 3, 4))
+	want := Polygon{VecXY(1, 2), VecXY(4, 2), VecXY(4, 6), VecXY(1, 6)}
+	if !got.Equals(want, e) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestPolygonEquals(t *testing.T) {
+	p := Polygon{VecXY(0, 0), VecXY(1, 0), VecXY(0, 1)}
+	if !p.Equals(Polygon{VecXY(0, 0), VecXY(1, 0), VecXY(0, 1)}, e) {
+		t.Errorf("%s should equal itself", p)
+	}
+	if p.Equals(Polygon{VecXY(0, 0), VecXY(1, 0)}, e) {
+		t.Errorf("%s should not equal a shorter Polygon", p)
+	}
+	if p.Equals(Polygon{VecXY(0, 0), VecXY(1, 0), VecXY(0, 2)}, e) {
+		t.Errorf("%s should not equal a different Polygon", p)
+	}
+}
+
+func TestPolygonArea(t *testing.T) {
+	cases := []struct {
+		p    Polygon
+		want float64
+	}{
+		{Polygon{}, 0},
+		{Polygon{VecXY(0, 0), VecXY(1, 0)}, 0},
+		{Polygon{VecXY(0, 0), VecXY(4, 0), VecXY(0, 3)}, 6},
+		{Polygon{VecXY(0, 3), VecXY(4, 0), VecXY(0, 0)}, 6},
+		{PolygonRect(RectXYWH(-1, -2, 3, 4)), 12},
+	}
+
+	for i, c := range cases {
+		got := c.p.Area()
+		if !fEqual(got, c.want) {
+			t.Errorf("case %d: got %f, want %f", i, got, c.want)
+		}
+	}
+}
+
+func TestPolygonCentroid(t *testing.T) {
+	cases := []struct {
+		p    Polygon
+		want Vec
+	}{
+		{Polygon{}, VecXY(0, 0)},
+		{Polygon{VecXY(0, 0), VecXY(2, 4)}, VecXY(1, 2)},
+		{Polygon{VecXY(0, 0), VecXY(3, 0), VecXY(0, 3)}, VecXY(1, 1)},
+		{Polygon{VecXY(0, 3), VecXY(3, 0), VecXY(0, 0)}, VecXY(1, 1)},
+		{PolygonRect(RectXYWH(-1, -2, 3, 4)), VecXY(0.5, 0)},
+	}
+
+	for i, c := range cases {
+		got := c.p.Centroid()
+		if !got.Equals(c.want, e) {
+			t.Errorf("case %d: got %s, want %s", i, got, c.want)
+		}
+	}
+}
+
+func TestPolygonBoundingRect(t *testing.T) {
+	cases := []struct {
+		p    Polygon
+		want Rect
+	}{
+		{Polygon{}, Rect{}},
+		{Polygon{VecXY(1, -1), VecXY(3, 2), VecXY(-2, 4)}, RectXYWH(-2, -1, 5, 5)},
+		{PolygonRect(RectXYWH(-1, -2, 3, 4)), RectXYWH(-1, -2, 3, 4)},
+	}
+
+	for i, c := range cases {
+		got := c.p.BoundingRect()
+		if got != c.want {
+			t.Errorf("case %d: got %s, want %s", i, got, c.want)
+		}
+	}
+}
+
+func TestPolygonMove(t *testing.T) {
+	p := Polygon{VecXY(0, 0), VecXY(1, 0), VecXY(0, 1)}
+	want := Polygon{VecXY(2, -1), VecXY(3, -1), VecXY(2, 0)}
+
+	got := p.Moved(2, -1)
+	if !got.Equals(want, e) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+	if p.Equals(want, e) {
+		t.Errorf("Moved modified the original: %s", p)
+	}
+
+	p.Move(2, -1)
+	if !p.Equals(want, e) {
+		t.Errorf("got %s, want %s", p, want)
+	}
+}
+
+func TestPolygonCollidePoint(t *testing.T) {
+	tri := Polygon{VecXY(0, 0), VecXY(4, 0), VecXY(0, 4)}
+	cases := []struct {
+		p    Polygon
+		x, y float64
+		want bool
+	}{
+		{tri, 1, 1, true},
+		{tri, 0, 0, false},
+		{tri, 2, 0, false},
+		{tri, 2, 2, false},
+		{tri, 3, 3, false},
+		{tri, -1, 1, false},
+		{Polygon{VecXY(0, 4), VecXY(4, 0), VecXY(0, 0)}, 1, 1, true},
+		{Polygon{VecXY(0, 0), VecXY(4, 0)}, 1, 0, false},
+		// Repeating a vertex.
+		{Polygon{VecXY(0, 0), VecXY(4, 0), VecXY(4, 4), VecXY(0, 4), VecXY(0, 0)}, 2, 2, true},
+		{Polygon{VecXY(0, 0), VecXY(4, 0), VecXY(4, 0), VecXY(4, 4), VecXY(0, 4)}, 2, 2, true},
+		{Polygon{VecXY(0, 0), VecXY(4, 0), VecXY(4, 4), VecXY(0, 4), VecXY(0, 0)}, 5, 2, false},
+		{Polygon{VecXY(0, 0), VecXY(4, 0), VecXY(4, 4), VecXY(0, 4), VecXY(0, 0)}, 4, 2, false},
+	}
+
+	for i, c := range cases {
+		got := c.p.CollidePoint(c.x, c.y)
+		if got != c.want {
+			t.Errorf("case %d: got %v, want %v", i, got, c.want)
+		}
+	}
+}
+
+func TestPolygonCollidePolygon(t *testing.T) {
+	tri := Polygon{VecXY(0, 0), VecXY(4, 0), VecXY(0, 4)}
+	cases := []struct {
+		p1, p2 Polygon
+		want   bool
+	}{
+		{tri, tri, true},
+		{tri, tri.Moved(3, 3), false},
+		{tri, tri.Moved(1.9, 1.9), true},
+		{tri, tri.Moved(2, 2), false},
+		{tri, tri.Moved(4, 0), false},
+		{tri, tri.Moved(3.9, 0), true},
+		{tri, Polygon{VecXY(3, 3), VecXY(5, 3), VecXY(3, 5)}, false},
+		{tri, Polygon{VecXY(1, 1), VecXY(1.5, 1), VecXY(1, 1.5)}, true},
+		{tri, Polygon{VecXY(1, 1), VecXY(1.5, 1)}, false},
+		// Repeating the first vertex at the end.
+		{Polygon{VecXY(0, 0), VecXY(4, 0), VecXY(4, 4), VecXY(0, 4), VecXY(0, 0)},
+			Polygon{VecXY(1, 1), VecXY(2, 1), VecXY(1, 2)}, true},
+		{Polygon{VecXY(0, 0), VecXY(4, 0), VecXY(4, 4), VecXY(0, 4), VecXY(0, 0)},
+			tri.Moved(5, 0), false},
+	}
+
+	for i, c := range cases {
+		got := c.p1.CollidePolygon(c.p2)
+		if got != c.want {
+			t.Errorf("case %d: got %v, want %v", i, got, c.want)
+		}
+		got = c.p2.CollidePolygon(c.p1)
+		if got != c.want {
+			t.Errorf("case %d reversed: got %v, want %v", i, got, c.want)
+		}
+	}
+}
+
+func TestPolygonCollideRect(t *testing.T) {
+	// A diamond centered at (0, 0).
+	diamond := Polygon{VecXY(0, -2), VecXY(2, 0), VecXY(0, 2), VecXY(-2, 0)}
+	cases := []struct {
+		p    Polygon
+		r    Rect
+		want bool
+	}{
+		{diamond, RectXYWH(-1, -1, 2, 2), true},
+		{diamond, RectXYWH(1, 1, 2, 2), false},
+		{diamond, RectXYWH(0.9, 0.9, 2, 2), true},
+		{diamond, RectXYWH(2, -1, 2, 2), false},
+		{diamond, RectXYWH(-10, -10, 20, 20), true},
+		{diamond, RectXYWH(1, 1, -2, -2), true},
+		// Repeating the first vertex at the end.
+		{append(diamond, diamond[0]), RectXYWH(-1, -1, 2, 2), true},
+		{append(diamond, diamond[0]), RectXYWH(1, 1, 2, 2), false},
+	}
+
+	for i, c := range cases {
+		got := c.p.CollideRect(c.r)
+		if got != c.want {
+			t.Errorf("case %d: got %v, want %v", i, got, c.want)
+		}
+		got = c.r.CollidePolygon(c.p)
+		if got != c.want {
+			t.Errorf("case %d reversed: got %v, want %v", i, got, c.want)
+		}
+	}
+}
+
+func TestPolygonCollideCircle(t *testing.T) {
+	square := PolygonRect(RectXYWH(0, 0, 4, 4))
+	tri := Polygon{VecXY(0, 0), VecXY(4, 0), VecXY(0, 4)}
+	cases := []struct {
+		p    Polygon
+		c    Circle
+		want bool
+	}{
+		{square, CircleXYR(2, 2, 1), true},
+		{square, CircleXYR(-1, 2, 1), false},
+		{square, CircleXYR(-0.9, 2, 1), true},
+		{square, CircleXYR(5, 5, 1.4), false},
+		{square, CircleXYR(5, 5, 1.5), true},
+		{square, CircleXYR(2, 2, 10), true},
+		{tri, CircleXYR(3, 3, 1), false},
+		{tri, CircleXYR(3, 3, 1.5), true},
+		{tri, CircleXYR(5, -1, 1.4), false},
+	}
+
+	for i, c := range cases {
+		got := c.p.CollideCircle(c.c)
+		if got != c.want {
+			t.Errorf("case %d: got %v, want %v", i, got, c.want)
+		}
+		got = c.c.CollidePolygon(c.p)
+		if got != c.want {
+			t.Errorf("case %d reversed: got %v, want %v", i, got, c.want)
+		}
+	}
+}
+
+func TestPolygonCollideList(t *testing.T) {
+	tri := Polygon{VecXY(0, 0), VecXY(4, 0), VecXY(0, 4)}
+	polys := []Polygon{tri.Moved(10, 0), tri.Moved(1, 1), tri.Moved(-1, -1)}
+	rects := []Rect{RectXYWH(5, 5, 1, 1), RectXYWH(-2, -2, 3, 3), RectXYWH(1, 1, 1, 1)}
+	circles := []Circle{CircleXYR(5, 5, 1), CircleXYR(-1, -1, 2), CircleXYR(1, 1, 1)}
+
+	if got, ok := tri.CollidePolygonList(polys); !ok || got != 1 {
+		t.Errorf("polygons: got %d, %v, want %d, %v", got, ok, 1, true)
+	}
+	if got := tri.CollidePolygonListAll(polys); !intListEqual(got, []int{1, 2}) {
+		t.Errorf("polygons all: got %v, want %v", got, []int{1, 2})
+	}
+	if _, ok := tri.CollidePolygonList(polys[:1]); ok {
+		t.Errorf("polygons: got collision, want none")
+	}
+
+	if got, ok := tri.CollideRectList(rects); !ok || got != 1 {
+		t.Errorf("rects: got %d, %v, want %d, %v", got, ok, 1, true)
+	}
+	if got := tri.CollideRectListAll(rects); !intListEqual(got, []int{1, 2}) {
+		t.Errorf("rects all: got %v, want %v", got, []int{1, 2})
+	}
+	if got := tri.CollideRectListAll([]Rect{}); !intListEqual(got, []int{}) {
+		t.Errorf("rects all: got %v, want %v", got, []int{})
+	}
+
+	if got, ok := tri.CollideCircleList(circles); !ok || got != 1 {
+		t.Errorf("circles: got %d, %v, want %d, %v", got, ok, 1, true)
+	}
+	if got := tri.CollideCircleListAll(circles); !intListEqual(got, []int{1, 2}) {
+		t.Errorf("circles all: got %v, want %v", got, []int{1, 2})
+	}
+
+	r := RectXYWH(0.5, 0.5, 1, 1)
+	if got, ok := r.CollidePolygonList(polys); !ok || got != 1 {
+		t.Errorf("rect: got %d, %v, want %d, %v", got, ok, 1, true)
+	}
+	if got := r.CollidePolygonListAll(polys); !intListEqual(got, []int{1, 2}) {
+		t.Errorf("rect all: got %v, want %v", got, []int{1, 2})
+	}
+
+	c := CircleXYR(1, 1, 0.5)
+	if got, ok := c.CollidePolygonList(polys); !ok || got != 1 {
+		t.Errorf("circle: got %d, %v, want %d, %v", got, ok, 1, true)
+	}
+	if got := c.CollidePolygonListAll(polys); !intListEqual(got, []int{1, 2}) {
+		t.Errorf("circle all: got %v, want %v", got, []int{1, 2})
+	}
+}
+
+func TestPolygonPenetrationPolygon(t *testing.T) {
+	tri := Polygon{VecXY(0, 0), VecXY(4, 0), VecXY(0, 4)}
+	cases := []struct {
+		p1, p2      Polygon
+		mtv, normal Vec
+		hit         bool
+	}{
+		{tri, tri.Moved(2, 2), Vec{}, Vec{}, false},
+		{tri, tri.Moved(4, 0), Vec{}, Vec{}, false},
+		{tri, tri.Moved(3.5, 0), VecXY(-0.25, -0.25), VecXY(-1, -1).Normalized(), true},
+		{tri, tri.Moved(1.5, 1.5), VecXY(-0.5, -0.5), VecXY(-1, -1).Normalized(), true},
+		{tri, Polygon{VecXY(1, 1), VecXY(1.5, 1), VecXY(1, 1.5)}, VecXY(-1, -1), VecXY(-1, -1).Normalized(), true},
+		// Repeating the first vertex at the end.
+		{append(tri, tri[0]), Polygon{VecXY(1, 1), VecXY(1.5, 1), VecXY(1, 1.5)}, VecXY(-1, -1), VecXY(-1, -1).Normalized(), true},
+	}
+
+	for i, c := range cases {
+		mtv, normal, hit := c.p1.PenetrationPolygon(c.p2)
+		if c.p1.CollidePolygon(c.p2) != hit {
+			t.Errorf("case %d: CollidePolygon disagrees with hit %v", i, hit)
+		}
+		if hit != c.hit || !mtv.Equals(c.mtv, e) || !normal.Equals(c.normal, e) {
+			t.Errorf("case %d: got %s, %s, %v, want %s, %s, %v", i, mtv, normal, hit, c.mtv, c.normal, c.hit)
+		}
+		if hit && c.p1.Moved(mtv.Times(1+1e-9).XY()).CollidePolygon(c.p2) {
+			t.Errorf("case %d: %s still collides with %s after moving by %s", i, c.p1, c.p2, mtv)
+		}
+	}
+}
+
+func TestPolygonPenetrationRect(t *testing.T) {
+	diamond := Polygon{VecXY(0, -2), VecXY(2, 0), VecXY(0, 2), VecXY(-2, 0)}
+	cases := []struct {
+		p           Polygon
+		r           Rect
+		mtv, normal Vec
+		hit         bool
+	}{
+		{diamond, RectXYWH(2, -1, 2, 2), Vec{}, Vec{}, false},
+		{diamond, RectXYWH(1.5, -1, 2, 2), VecXY(-0.5, 0), VecXY(-1, 0), true},
+		{diamond, RectXYWH(-1.5, 1.5, 2, 2), VecXY(0, -0.5), VecXY(0, -1), true},
+	}
+
+	for i, c := range cases {
+		mtv, normal, hit := c.p.PenetrationRect(c.r)
+		if hit != c.hit || !mtv.Equals(c.mtv, e) || !normal.Equals(c.normal, e) {
+			t.Errorf("case %d: got %s, %s, %v, want %s, %s, %v", i, mtv, normal, hit, c.mtv, c.normal, c.hit)
+		}
+	}
+}
+
+func TestPolygonPenetrationCircle(t *testing.T) {
+	square := PolygonRect(RectXYWH(0, 0, 4, 4))
+	cases := []struct {
+		p           Polygon
+		c           Circle
+		mtv, normal Vec
+		hit         bool
+	}{
+		{square, CircleXYR(-1, 2, 1), Vec{}, Vec{}, false},
+		{square, CircleXYR(-0.5, 2, 1), VecXY(0.5, 0), VecXY(1, 0), true},
+		{square, CircleXYR(5, 5, 2), VecXY(2-math.Sqrt2, 2-math.Sqrt2).Times(-math.Sqrt2 / 2), VecXY(-1, -1).Normalized(), true},
+	}
+
+	for i, c := range cases {
+		mtv, normal, hit := c.p.PenetrationCircle(c.c)
+		if hit != c.hit || !mtv.Equals(c.mtv, e) || !normal.Equals(c.normal, e) {
+			t.Errorf("case %d: got %s, %s, %v, want %s, %s, %v", i, mtv, normal, hit, c.mtv, c.normal, c.hit)
+		}
+	}
+}
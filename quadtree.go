@@ -0,0 +1,257 @@
+package geo
+
+import "sort"
+
+// Quadtree is a spatial index of Rects and Circles, each identified by an index chosen by
+// the caller, within fixed bounds. Each node splits into four quarters once it holds too
+// many shapes, and each shape lives in the deepest node that contains it, so a query only
+// visits the nodes it overlaps. Shapes outside of the bounds are allowed but are always
+// checked.
+type Quadtree struct {
+	root     *qtNode
+	items    map[int]*qtItem
+	maxItems int
+	maxDepth int
+}
+
+type qtNode struct {
+	bounds   Rect
+	depth    int
+	parent   *qtNode
+	children []*qtNode
+	items    []*qtItem
+	// count is the number of items in this node and all of its descendants.
+	count int
+}
+
+type qtItem struct {
+	i      int
+	shape  shape
+	bounds Rect
+	node   *qtNode
+}
+
+// NewQuadtree creates an empty Quadtree covering bounds. A node is split into 4 once it has
+// more than maxItems shapes, unless it is already maxDepth levels deep.
+func NewQuadtree(bounds Rect, maxItems, maxDepth int) *Quadtree {
+	return &Quadtree{
+		root:     &qtNode{bounds: bounds.Normalized()},
+		items:    make(map[int]*qtItem),
+		maxItems: maxItems,
+		maxDepth: maxDepth,
+	}
+}
+
+// Len returns the number of shapes in the Quadtree.
+func (q *Quadtree) Len() int {
+	return len(q.items)
+}
+
+// InsertRect adds the Rect to the Quadtree with the index i. If i is already in the Quadtree
+// then it is moved instead.
+func (q *Quadtree) InsertRect(i int, r Rect) {
+	q.insert(i, rectShape(r))
+}
+
+// InsertCircle adds the Circle to the Quadtree with the index i. If i is already in the
+// Quadtree then it is moved instead.
+func (q *Quadtree) InsertCircle(i int, c Circle) {
+	q.insert(i, circleShape(c))
+}
+
+func (q *Quadtree) insert(i int, s shape) {
+	if _, ok := q.items[i]; ok {
+		q.move(i, s)
+		return
+	}
+	item := &qtItem{i: i, shape: s, bounds: s.bounds()}
+	q.items[i] = item
+	q.root.insert(item, q.maxItems, q.maxDepth)
+}
+
+// MoveRect changes the shape with index i to the Rect. If i isn't in the Quadtree then it
+// is inserted.
+func (q *Quadtree) MoveRect(i int, r Rect) {
+	q.move(i, rectShape(r))
+}
+
+// MoveCircle changes the shape with index i to the Circle. If i isn't in the Quadtree then
+// it is inserted.
+func (q *Quadtree) MoveCircle(i int, c Circle) {
+	q.move(i, circleShape(c))
+}
+
+func (q *Quadtree) move(i int, s shape) {
+	item, ok := q.items[i]
+	if !ok {
+		q.insert(i, s)
+		return
+	}
+	bounds := s.bounds()
+	// Small movements usually stay within the same leaf, in which case nothing else changes.
+	if item.node.children == nil && (item.node.bounds.Contains(bounds) || item.node == q.root) {
+		item.shape, item.bounds = s, bounds
+		return
+	}
+	q.Remove(i)
+	q.insert(i, s)
+}
+
+// Remove removes the shape with index i and returns true, or returns false if i isn't in
+// the Quadtree.
+func (q *Quadtree) Remove(i int) bool {
+	item, ok := q.items[i]
+	if !ok {
+		return false
+	}
+	delete(q.items, i)
+
+	n := item.node
+	for j, other := range n.items {
+		if other == item {
+			last := len(n.items) - 1
+			n.items[j] = n.items[last]
+			n.items[last] = nil
+			n.items = n.items[:last]
+			break
+		}
+	}
+
+	// Merge the highest ancestor that no longer needs to be split.
+	var merge *qtNode
+	for ; n != nil; n = n.parent {
+		n.count--
+		if n.children != nil && n.count <= q.maxItems {
+			merge = n
+		}
+	}
+	if merge != nil {
+		merge.merge()
+	}
+	return true
+}
+
+// QueryRect returns a sorted list of indices of the shapes that collide with the Rect, or
+// an empty list if none.
+func (q *Quadtree) QueryRect(r Rect) []int {
+	list := make([]int, 0)
+	q.root.query(
+		func(n *qtNode) bool { return n == q.root || r.CollideRect(n.bounds) },
+		func(s shape) bool { return s.collideRect(r) },
+		&list)
+	sort.Ints(list)
+	return list
+}
+
+// QueryCircle returns a sorted list of indices of the shapes that collide with the Circle,
+// or an empty list if none.
+func (q *Quadtree) QueryCircle(c Circle) []int {
+	list := make([]int, 0)
+	q.root.query(
+		func(n *qtNode) bool { return n == q.root || c.CollideRect(n.bounds) },
+		func(s shape) bool { return s.collideCircle(c) },
+		&list)
+	sort.Ints(list)
+	return list
+}
+
+// QueryPoint returns a sorted list of indices of the shapes that contain the point, or an
+// empty list if none.
+func (q *Quadtree) QueryPoint(x, y float64) []int {
+	list := make([]int, 0)
+	q.root.query(
+		func(n *qtNode) bool { return n == q.root || n.bounds.CollidePoint(x, y) },
+		func(s shape) bool { return s.collidePoint(x, y) },
+		&list)
+	sort.Ints(list)
+	return list
+}
+
+// QueryRay returns a sorted list of indices of the shapes that the Ray intersects in front
+// of its origin, including the shapes that contain the origin, or an empty list if none.
+func (q *Quadtree) QueryRay(r Ray) []int {
+	list := make([]int, 0)
+	q.root.query(
+		func(n *qtNode) bool {
+			_, tMax, hit := r.IntersectRect(n.bounds)
+			return n == q.root || hit && tMax >= 0
+		},
+		func(s shape) bool {
+			_, _, hit := s.intersectRay(r)
+			return hit
+		},
+		&list)
+	sort.Ints(list)
+	return list
+}
+
+func (n *qtNode) insert(item *qtItem, maxItems, maxDepth int) {
+	n.count++
+	for _, child := range n.children {
+		if child.bounds.Contains(item.bounds) {
+			child.insert(item, maxItems, maxDepth)
+			return
+		}
+	}
+	item.node = n
+	n.items = append(n.items, item)
+	if n.children == nil && len(n.items) > maxItems && n.depth < maxDepth {
+		n.split(maxItems, maxDepth)
+	}
+}
+
+// split divides a leaf into 4 quadrants and moves down the items that fit in one.
+func (n *qtNode) split(maxItems, maxDepth int) {
+	w, h := n.bounds.W/2, n.bounds.H/2
+	n.children = []*qtNode{
+		{bounds: RectXYWH(n.bounds.X, n.bounds.Y, w, h)},
+		{bounds: RectXYWH(n.bounds.X+w, n.bounds.Y, w, h)},
+		{bounds: RectXYWH(n.bounds.X, n.bounds.Y+h, w, h)},
+		{bounds: RectXYWH(n.bounds.X+w, n.bounds.Y+h, w, h)},
+	}
+	for _, child := range n.children {
+		child.depth = n.depth + 1
+		child.parent = n
+	}
+
+	items := n.items
+	n.items = nil
+	n.count -= len(items)
+	for _, item := range items {
+		n.insert(item, maxItems, maxDepth)
+	}
+}
+
+// merge moves all items in the descendants of n into n and removes its children.
+func (n *qtNode) merge() {
+	var collect func(child *qtNode)
+	collect = func(child *qtNode) {
+		for _, item := range child.items {
+			item.node = n
+			n.items = append(n.items, item)
+		}
+		for _, grandchild := range child.children {
+			collect(grandchild)
+		}
+	}
+	for _, child := range n.children {
+		collect(child)
+	}
+	n.children = nil
+}
+
+// query appends the index of each item for which match returns true, only descending into
+// nodes for which visit returns true.
+func (n *qtNode) query(visit func(*qtNode) bool, match func(shape) bool, list *[]int) {
+	if !visit(n) {
+		return
+	}
+	for _, item := range n.items {
+		if match(item.shape) {
+			*list = append(*list, item.i)
+		}
+	}
+	for _, child := range n.children {
+		child.query(visit, match, list)
+	}
+}
@@ -0,0 +1,183 @@
+package geo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func randRect(bounds Rect, maxSize float64) Rect {
+	return RectXYWH(
+		rand.Float64()*bounds.W+bounds.X, rand.Float64()*bounds.H+bounds.Y,
+		rand.Float64()*maxSize, rand.Float64()*maxSize,
+	)
+}
+
+func randCircle(bounds Rect, maxR float64) Circle {
+	return CircleXYR(rand.Float64()*bounds.W+bounds.X, rand.Float64()*bounds.H+bounds.Y, rand.Float64()*maxR)
+}
+
+// rectIndex is the part of Quadtree, SpatialHash, and AABBTree that checkRectQueries and
+// removeRects use.
+type rectIndex interface {
+	QueryRect(r Rect) []int
+	QueryCircle(c Circle) []int
+	QueryPoint(x, y float64) []int
+	Remove(i int) bool
+}
+
+// checkRectQueries compares random queries of index, which holds rects, against checking
+// every Rect.
+func checkRectQueries(t *testing.T, stage string, index rectIndex, rects []Rect, bounds Rect) {
+	for trial := 0; trial < 50; trial++ {
+		r := randRect(bounds, 50)
+		if got, want := index.QueryRect(r), r.CollideRectListAll(rects); !intListEqual(got, want) {
+			t.Errorf("%s: rect %s: got %v, want %v", stage, r, got, want)
+		}
+		c := randCircle(bounds, 30)
+		if got, want := index.QueryCircle(c), c.CollideRectListAll(rects); !intListEqual(got, want) {
+			t.Errorf("%s: circle %s: got %v, want %v", stage, c, got, want)
+		}
+		x, y := rand.Float64()*bounds.W+bounds.X, rand.Float64()*bounds.H+bounds.Y
+		want := []int{}
+		for i, r := range rects {
+			if r.CollidePoint(x, y) {
+				want = append(want, i)
+			}
+		}
+		if got := index.QueryPoint(x, y); !intListEqual(got, want) {
+			t.Errorf("%s: point %f, %f: got %v, want %v", stage, x, y, got, want)
+		}
+	}
+}
+
+// removeRects removes all but every third Rect from index. They are replaced in rects with
+// ones far away, so the indices of the rest stay the same.
+func removeRects(t *testing.T, index rectIndex, rects []Rect) {
+	for i := range rects {
+		if i%3 != 0 {
+			if !index.Remove(i) {
+				t.Errorf("remove %d: got false, want true", i)
+			}
+			rects[i] = Rect{X: 1000, Y: 1000}
+		}
+	}
+	if index.Remove(1) {
+		t.Errorf("remove twice: got true, want false")
+	}
+}
+
+func TestQuadtreeRects(t *testing.T) {
+	bounds := RectXYWH(-100, -100, 200, 200)
+	q := NewQuadtree(bounds, 4, 6)
+	// Some of the Rects extend past the bounds of the Quadtree.
+	rects := make([]Rect, 200)
+	for i := range rects {
+		rects[i] = randRect(bounds.Inflated(20, 20), 30)
+		q.InsertRect(i, rects[i])
+	}
+	if q.Len() != len(rects) {
+		t.Errorf("len: got %d, want %d", q.Len(), len(rects))
+	}
+
+	checkRectQueries(t, "inserted", q, rects, bounds)
+
+	for i := range rects {
+		if rand.Intn(2) == 0 {
+			rects[i] = randRect(bounds, 30)
+		} else {
+			rects[i].Move(rand.Float64()*4-2, rand.Float64()*4-2)
+		}
+		q.MoveRect(i, rects[i])
+	}
+	checkRectQueries(t, "moved", q, rects, bounds)
+
+	removeRects(t, q, rects)
+	checkRectQueries(t, "removed", q, rects, bounds)
+
+	for i := range rects {
+		q.Remove(i)
+	}
+	if q.Len() != 0 || q.root.children != nil || len(q.root.items) != 0 {
+		t.Errorf("empty: got len %d, children %v, items %v", q.Len(), q.root.children, q.root.items)
+	}
+}
+
+func TestQuadtreeCircles(t *testing.T) {
+	bounds := RectXYWH(-100, -100, 200, 200)
+	q := NewQuadtree(bounds, 4, 6)
+	circles := make([]Circle, 200)
+	for i := range circles {
+		circles[i] = randCircle(bounds, 15)
+		q.InsertCircle(i, circles[i])
+	}
+
+	for trial := 0; trial < 50; trial++ {
+		r := randRect(bounds, 50)
+		if got, want := q.QueryRect(r), r.CollideCircleListAll(circles); !intListEqual(got, want) {
+			t.Errorf("rect %s: got %v, want %v", r, got, want)
+		}
+		c := randCircle(bounds, 30)
+		if got, want := q.QueryCircle(c), c.CollideCircleListAll(circles); !intListEqual(got, want) {
+			t.Errorf("circle %s: got %v, want %v", c, got, want)
+		}
+	}
+
+	for i := range circles {
+		circles[i].Move(rand.Float64()*10-5, rand.Float64()*10-5)
+		q.MoveCircle(i, circles[i])
+	}
+	for trial := 0; trial < 50; trial++ {
+		c := randCircle(bounds, 30)
+		if got, want := q.QueryCircle(c), c.CollideCircleListAll(circles); !intListEqual(got, want) {
+			t.Errorf("moved: circle %s: got %v, want %v", c, got, want)
+		}
+	}
+}
+
+func TestQuadtreeQueryRay(t *testing.T) {
+	q := NewQuadtree(RectXYWH(0, 0, 100, 100), 1, 4)
+	q.InsertRect(0, RectXYWH(10, 10, 5, 5))
+	q.InsertRect(1, RectXYWH(80, 11, 5, 5))
+	q.InsertCircle(2, CircleXYR(50, 12, 3))
+	q.InsertCircle(3, CircleXYR(50, 80, 3))
+	q.InsertRect(4, RectXYWH(0, 5, 3, 10))
+
+	cases := []struct {
+		r    Ray
+		want []int
+	}{
+		{Ray{VecXY(5, 12), VecXY(1, 0)}, []int{0, 1, 2}},
+		{Ray{VecXY(60, 12), VecXY(1, 0)}, []int{1}},
+		{Ray{VecXY(60, 12), VecXY(-1, 0)}, []int{0, 2, 4}},
+		{Ray{VecXY(12, 12), VecXY(1, 0)}, []int{0, 1, 2}},
+		{Ray{VecXY(50, 0), VecXY(0, 1)}, []int{2, 3}},
+		{Ray{VecXY(50, 0), VecXY(0, -1)}, []int{}},
+		{Ray{VecXY(-10, 50), VecXY(1, 0)}, []int{}},
+	}
+
+	for i, c := range cases {
+		got := q.QueryRay(c.r)
+		if !intListEqual(got, c.want) {
+			t.Errorf("case %d: got %v, want %v", i, got, c.want)
+		}
+	}
+}
+
+func TestQuadtreeReinsert(t *testing.T) {
+	q := NewQuadtree(RectXYWH(0, 0, 100, 100), 1, 4)
+	q.InsertRect(0, RectXYWH(10, 10, 5, 5))
+	q.InsertRect(0, RectXYWH(80, 80, 5, 5))
+	q.MoveCircle(1, CircleXYR(50, 50, 1))
+	if q.Len() != 2 {
+		t.Errorf("len: got %d, want %d", q.Len(), 2)
+	}
+	if got := q.QueryPoint(12, 12); !intListEqual(got, []int{}) {
+		t.Errorf("old position: got %v, want %v", got, []int{})
+	}
+	if got := q.QueryPoint(82, 82); !intListEqual(got, []int{0}) {
+		t.Errorf("new position: got %v, want %v", got, []int{0})
+	}
+	if got := q.QueryPoint(50, 50); !intListEqual(got, []int{1}) {
+		t.Errorf("moved before insert: got %v, want %v", got, []int{1})
+	}
+}
@@ -0,0 +1,87 @@
+package geo
+
+import (
+	"image"
+	"sync"
+)
+
+// NoiseGrid samples fn over region, at z = 0, with scale pixels per unit, and returns the
+// values in row-major order along with the bounds of the grid. The bounds are region scaled
+// by scale and converted with Rect.Rectangle, and pixel (px, py) is sampled at its center,
+// ((px+0.5)/scale, (py+0.5)/scale). Because of this, neighboring regions sampled at the same
+// scale line up seamlessly. The value for pixel (px, py) is at index
+//  (py-bounds.Min.Y)*bounds.Dx() + (px-bounds.Min.X)
+// If workers is more than 1 then the rows are split between that many goroutines, in which
+// case fn must be safe for concurrent use.
+func NoiseGrid(fn NoiseFn, region Rect, scale float64, workers int) (grid []float64, bounds image.Rectangle) {
+	bounds = rasterBounds(region, scale)
+	grid = make([]float64, bounds.Dx()*bounds.Dy())
+	rasterize(fn, bounds, scale, workers, func(i int, v float64) {
+		grid[i] = v
+	})
+	return grid, bounds
+}
+
+// NoiseGray is like NoiseGrid but returns a grayscale image, where 0 is black and 1 is
+// white.
+func NoiseGray(fn NoiseFn, region Rect, scale float64, workers int) *image.Gray {
+	img := image.NewGray(rasterBounds(region, scale))
+	rasterize(fn, img.Rect, scale, workers, func(i int, v float64) {
+		img.Pix[i] = uint8(Clamp(v, 0, 1)*0xff + 0.5)
+	})
+	return img
+}
+
+// NoiseGray16 is like NoiseGray but uses 16 bits per pixel, which avoids banding when the
+// result is used as a heightmap.
+func NoiseGray16(fn NoiseFn, region Rect, scale float64, workers int) *image.Gray16 {
+	img := image.NewGray16(rasterBounds(region, scale))
+	rasterize(fn, img.Rect, scale, workers, func(i int, v float64) {
+		c := uint16(Clamp(v, 0, 1)*0xffff + 0.5)
+		img.Pix[2*i] = uint8(c >> 8)
+		img.Pix[2*i+1] = uint8(c)
+	})
+	return img
+}
+
+func rasterBounds(region Rect, scale float64) image.Rectangle {
+	region.Normalize()
+	return RectXYWH(region.X*scale, region.Y*scale, region.W*scale, region.H*scale).Rectangle()
+}
+
+// rasterize calls set with the row-major index and value of each pixel in bounds.
+func rasterize(fn NoiseFn, bounds image.Rectangle, scale float64, workers int, set func(i int, v float64)) {
+	w := bounds.Dx()
+	row := func(py int) {
+		i := (py - bounds.Min.Y) * w
+		y := (float64(py) + 0.5) / scale
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			set(i, fn((float64(px)+0.5)/scale, y, 0))
+			i++
+		}
+	}
+
+	if workers <= 1 {
+		for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+			row(py)
+		}
+		return
+	}
+
+	rows := make(chan int, bounds.Dy())
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		rows <- py
+	}
+	close(rows)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for j := 0; j < workers; j++ {
+		go func() {
+			defer wg.Done()
+			for py := range rows {
+				row(py)
+			}
+		}()
+	}
+	wg.Wait()
+}
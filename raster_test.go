@@ -0,0 +1,78 @@
+package geo
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestNoiseGrid(t *testing.T) {
+	fn := func(x, y, z float64) float64 { return x + 100*y + 1000*z }
+	cases := []struct {
+		region     Rect
+		scale      float64
+		wantBounds image.Rectangle
+		want       []float64
+	}{
+		{RectXYWH(0, 0, 2, 1), 1, image.Rect(0, 0, 2, 1), []float64{50.5, 51.5}},
+		{RectXYWH(1, 2, 1, 1), 2, image.Rect(2, 4, 4, 6), []float64{
+			226.25, 226.75,
+			276.25, 276.75,
+		}},
+		{RectXYWH(0, 1, 1, -1), 1, image.Rect(0, 0, 1, 1), []float64{50.5}},
+		{RectXYWH(3, 3, 0, 5), 1, image.Rect(3, 3, 3, 8), []float64{}},
+	}
+
+	for i, c := range cases {
+		for _, workers := range []int{0, 1, 3} {
+			got, bounds := NoiseGrid(fn, c.region, c.scale, workers)
+			if bounds != c.wantBounds {
+				t.Errorf("case %d: workers %d: bounds: got %v, want %v", i, workers, bounds, c.wantBounds)
+			}
+			if len(got) != len(c.want) {
+				t.Errorf("case %d: workers %d: got %v, want %v", i, workers, got, c.want)
+				continue
+			}
+			for j := range got {
+				if math.Abs(got[j]-c.want[j]) > e {
+					t.Errorf("case %d: workers %d: got %v, want %v", i, workers, got, c.want)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestNoiseGray(t *testing.T) {
+	region := RectXYWH(-2, 3, 4, 2)
+	scale := 8.0
+	fn := NewFractal(Perlin, 3).At
+	grid, bounds := NoiseGrid(fn, region, scale, 1)
+
+	gray := NoiseGray(fn, region, scale, 4)
+	gray16 := NoiseGray16(fn, region, scale, 4)
+	if gray.Bounds() != bounds || gray16.Bounds() != bounds {
+		t.Fatalf("bounds: got %v and %v, want %v", gray.Bounds(), gray16.Bounds(), bounds)
+	}
+	i := 0
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			if got, want := gray.GrayAt(px, py).Y, uint8(math.Floor(grid[i]*0xff+0.5)); got != want {
+				t.Errorf("gray %d, %d: got %d, want %d", px, py, got, want)
+			}
+			if got, want := gray16.Gray16At(px, py).Y, uint16(math.Floor(grid[i]*0xffff+0.5)); got != want {
+				t.Errorf("gray16 %d, %d: got %d, want %d", px, py, got, want)
+			}
+			i++
+		}
+	}
+
+	// Out of range values are clamped.
+	img := NoiseGray16(func(x, y, z float64) float64 { return x - 1 }, RectXYWH(0, 0, 3, 1), 1, 1)
+	want := []uint16{0, 0x8000, 0xffff}
+	for px, w := range want {
+		if got := img.Gray16At(px, 0).Y; got != w {
+			t.Errorf("clamp %d: got %d, want %d", px, got, w)
+		}
+	}
+}
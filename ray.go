@@ -92,3 +92,8 @@ func (r Ray) IntersectLine(v1, v2 Vec) (t float64, hit bool) {
 	t2 := a.Dot(c) / b.Dot(c)
 	return t1, 0 <= t2 && t2 <= 1
 }
+
+// IntersectSegment is the same as IntersectLine using the end points of the Segment.
+func (r Ray) IntersectSegment(s Segment) (t float64, hit bool) {
+	return r.IntersectLine(s.A, s.B)
+}
@@ -189,3 +189,13 @@ func TestRayIntersectLine(t *testing.T) {
 		}
 	}
 }
+
+func TestRayIntersectSegment(t *testing.T) {
+	r := Ray{VecXY(-5, 2), VecXY(1, -1)}
+	s := SegmentXY(-3, 3, -3, -3)
+	gotT, gotHit := r.IntersectSegment(s)
+	wantT, wantHit := r.IntersectLine(s.A, s.B)
+	if gotT != wantT || gotHit != wantHit {
+		t.Errorf("got %f, %v, want %f, %v", gotT, gotHit, wantT, wantHit)
+	}
+}
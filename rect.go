@@ -461,3 +461,127 @@ func (r Rect) CollideCircleListAll(cs []Circle) []int {
 	}
 	return list
 }
+
+// CollidePolygon returns true if the Rect is colliding with the Polygon.
+func (r Rect) CollidePolygon(p Polygon) bool {
+	return p.CollideRect(r)
+}
+
+// CollidePolygonList returns the index of the first Polygon the Rect collides with. If there
+// is no collision then ok is false and i is undefined.
+func (r Rect) CollidePolygonList(ps []Polygon) (i int, ok bool) {
+	for i, p := range ps {
+		if p.CollideRect(r) {
+			return i, true
+		}
+	}
+	return
+}
+
+// CollidePolygonListAll returns a list of indices of the Polygons that collide with the Rect,
+// or an empty list if none.
+func (r Rect) CollidePolygonListAll(ps []Polygon) []int {
+	list := make([]int, 0, len(ps))
+	for i, p := range ps {
+		if p.CollideRect(r) {
+			list = append(list, i)
+		}
+	}
+	return list
+}
+
+// PenetrationRect returns the minimum translation vector (MTV) that would move this Rect out
+// of other, along with the unit contact normal pointing from other towards this Rect. If the
+// Rects don't collide then hit is false and mtv and normal are the zero vector.
+func (r Rect) PenetrationRect(other Rect) (mtv, normal Vec, hit bool) {
+	if !r.CollideRect(other) {
+		return
+	}
+	dx, nx := other.Right()-r.X, 1.0
+	if d := r.Right() - other.X; d < dx {
+		dx, nx = d, -1
+	}
+	dy, ny := other.Bottom()-r.Y, 1.0
+	if d := r.Bottom() - other.Y; d < dy {
+		dy, ny = d, -1
+	}
+	if dx <= dy {
+		return Vec{X: nx * dx}, Vec{X: nx}, true
+	}
+	return Vec{Y: ny * dy}, Vec{Y: ny}, true
+}
+
+// PenetrationCircle returns the minimum translation vector (MTV) that would move the Rect
+// out of the Circle, along with the unit contact normal pointing from the Circle towards the
+// Rect. If they don't collide then hit is false and mtv and normal are the zero vector.
+func (r Rect) PenetrationCircle(c Circle) (mtv, normal Vec, hit bool) {
+	mtv, normal, hit = c.PenetrationRect(r)
+	return mtv.Times(-1), normal.Times(-1), hit
+}
+
+// PenetrationPolygon returns the minimum translation vector (MTV) that would move the Rect
+// out of the Polygon, along with the unit contact normal pointing from the Polygon towards
+// the Rect. If they don't collide then hit is false and mtv and normal are the zero vector.
+func (r Rect) PenetrationPolygon(p Polygon) (mtv, normal Vec, hit bool) {
+	mtv, normal, hit = p.PenetrationRect(r)
+	return mtv.Times(-1), normal.Times(-1), hit
+}
+
+// SweepRect tests whether this Rect, moving by vel, hits target. It returns the fraction of
+// vel at which they first touch, in [0, 1], along with the unit normal of the side of target
+// that was hit. If the Rects already collide then t is 0 and normal is the same as from
+// PenetrationRect. If hit is false then t and normal are undefined.
+func (r Rect) SweepRect(vel Vec, target Rect) (t float64, normal Vec, hit bool) {
+	if _, normal, hit := r.PenetrationRect(target); hit {
+		return 0, normal, true
+	}
+	r.Normalize()
+	target.Normalize()
+	// Sweeping r against target is the same as sweeping r's top left corner against target
+	// grown by r's size.
+	expanded := Rect{X: target.X - r.W, Y: target.Y - r.H, W: target.W + r.W, H: target.H + r.H}
+	t, _, normal, hit = expanded.sweepPoint(Vec{X: r.X, Y: r.Y}, vel)
+	if !hit || t < 0 || t > 1 {
+		return 0, Vec{}, false
+	}
+	return t, normal, true
+}
+
+// SweepCircle tests whether the Rect, moving by vel, hits the Circle. It returns the fraction
+// of vel at which they first touch, in [0, 1], along with the unit normal pointing from the
+// Circle towards the Rect at the point of contact. If they already collide then t is 0 and
+// normal is the same as from PenetrationCircle. If hit is false then t and normal are
+// undefined.
+func (r Rect) SweepCircle(vel Vec, c Circle) (t float64, normal Vec, hit bool) {
+	t, normal, hit = c.SweepRect(vel.Times(-1), r)
+	return t, normal.Times(-1), hit
+}
+
+// sweepPoint returns the fractions of vel at which a point starting at origin and moving by
+// vel enters and exits the Rect, along with the normal of the side it enters through. The
+// Rect must be normalized. Touching the edge of the Rect doesn't count as entering it.
+func (r Rect) sweepPoint(origin, vel Vec) (tEnter, tExit float64, normal Vec, hit bool) {
+	// Same slab approach as Ray.IntersectRect.
+	tEnter, tExit = math.Inf(-1), math.Inf(1)
+	slab := func(o, v, min, max float64, n Vec) bool {
+		if v == 0 {
+			return o > min && o < max
+		}
+		t1 := (min - o) / v
+		t2 := (max - o) / v
+		if v < 0 {
+			t1, t2 = t2, t1
+			n = n.Times(-1)
+		}
+		if t1 > tEnter {
+			tEnter, normal = t1, n
+		}
+		tExit = math.Min(tExit, t2)
+		return true
+	}
+	if !slab(origin.X, vel.X, r.Left(), r.Right(), Vec{X: -1}) ||
+		!slab(origin.Y, vel.Y, r.Top(), r.Bottom(), Vec{Y: -1}) {
+		return
+	}
+	return tEnter, tExit, normal, tEnter < tExit
+}
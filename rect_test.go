@@ -1,6 +1,9 @@
 package geo
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestMakeRect(t *testing.T) {
 	want := Rect{X: 1, Y: 2, W: 2, H: 3}
@@ -532,3 +535,136 @@ func TestRectCollideCircleListAll(t *testing.T) {
 		}
 	}
 }
+
+func TestRectPenetrationRect(t *testing.T) {
+	cases := []struct {
+		r1, r2      Rect
+		mtv, normal Vec
+		hit         bool
+	}{
+		{RectXYWH(0, 0, 2, 2), RectXYWH(3, 0, 2, 2), Vec{}, Vec{}, false},
+		{RectXYWH(0, 0, 2, 2), RectXYWH(2, 0, 2, 2), Vec{}, Vec{}, false},
+		{RectXYWH(0, 0, 2, 2), RectXYWH(1.5, 0, 2, 2), VecXY(-0.5, 0), VecXY(-1, 0), true},
+		{RectXYWH(0, 0, 2, 2), RectXYWH(-1.5, 0.5, 2, 2), VecXY(0.5, 0), VecXY(1, 0), true},
+		{RectXYWH(0, 0, 2, 2), RectXYWH(0.5, 1.5, 2, 2), VecXY(0, -0.5), VecXY(0, -1), true},
+		{RectXYWH(0, 0, 2, 2), RectXYWH(0.5, -1.5, 2, 2), VecXY(0, 0.5), VecXY(0, 1), true},
+		{RectXYWH(1, 0.5, 1, 1), RectXYWH(0, 0, 10, 4), VecXY(0, -1.5), VecXY(0, -1), true},
+	}
+
+	for i, c := range cases {
+		mtv, normal, hit := c.r1.PenetrationRect(c.r2)
+		if hit != c.hit || !mtv.Equals(c.mtv, e) || !normal.Equals(c.normal, e) {
+			t.Errorf("case %d: got %s, %s, %v, want %s, %s, %v", i, mtv, normal, hit, c.mtv, c.normal, c.hit)
+		}
+		if hit && c.r1.Moved(mtv.XY()).CollideRect(c.r2) {
+			t.Errorf("case %d: %s still collides with %s after moving by %s", i, c.r1, c.r2, mtv)
+		}
+	}
+}
+
+func TestRectPenetrationCircle(t *testing.T) {
+	cases := []struct {
+		r           Rect
+		c           Circle
+		mtv, normal Vec
+		hit         bool
+	}{
+		{RectXYWH(0, 0, 2, 2), CircleXYR(4, 1, 1), Vec{}, Vec{}, false},
+		{RectXYWH(0, 0, 2, 2), CircleXYR(2.5, 1, 1), VecXY(-0.5, 0), VecXY(-1, 0), true},
+		{RectXYWH(0, 0, 2, 2), CircleXYR(1, -0.5, 1), VecXY(0, 0.5), VecXY(0, 1), true},
+	}
+
+	for i, c := range cases {
+		mtv, normal, hit := c.r.PenetrationCircle(c.c)
+		if hit != c.hit || !mtv.Equals(c.mtv, e) || !normal.Equals(c.normal, e) {
+			t.Errorf("case %d: got %s, %s, %v, want %s, %s, %v", i, mtv, normal, hit, c.mtv, c.normal, c.hit)
+		}
+	}
+}
+
+func TestRectPenetrationPolygon(t *testing.T) {
+	cases := []struct {
+		r           Rect
+		p           Polygon
+		mtv, normal Vec
+		hit         bool
+	}{
+		{RectXYWH(0, 0, 2, 2), Polygon{VecXY(3, 0), VecXY(5, 0), VecXY(3, 2)}, Vec{}, Vec{}, false},
+		{RectXYWH(0, 0, 2, 2), Polygon{VecXY(1.5, 0), VecXY(5, 0), VecXY(1.5, 2)}, VecXY(-0.5, 0), VecXY(-1, 0), true},
+	}
+
+	for i, c := range cases {
+		mtv, normal, hit := c.r.PenetrationPolygon(c.p)
+		if hit != c.hit || !mtv.Equals(c.mtv, e) || !normal.Equals(c.normal, e) {
+			t.Errorf("case %d: got %s, %s, %v, want %s, %s, %v", i, mtv, normal, hit, c.mtv, c.normal, c.hit)
+		}
+	}
+}
+
+func TestRectSweepRect(t *testing.T) {
+	type res struct {
+		t      float64
+		normal Vec
+		hit    bool
+	}
+	wall := RectXYWH(10, -10, 1, 20)
+	cases := []struct {
+		r      Rect
+		vel    Vec
+		target Rect
+		want   res
+	}{
+		// A fast, small Rect that would tunnel through the wall.
+		{RectXYWH(0, 0, 1, 1), VecXY(100, 0), wall, res{0.09, VecXY(-1, 0), true}},
+		{RectXYWH(0, 0, 1, 1), VecXY(8, 0), wall, res{}},
+		{RectXYWH(0, 0, 1, 1), VecXY(9, 0), wall, res{1, VecXY(-1, 0), true}},
+		{RectXYWH(0, 0, 1, 1), VecXY(-100, 0), wall, res{}},
+		{RectXYWH(20, 0, 1, 1), VecXY(-20, 0), wall, res{0.45, VecXY(1, 0), true}},
+		{RectXYWH(10, 20, 1, 1), VecXY(0, -20), wall, res{0.5, VecXY(0, 1), true}},
+		{RectXYWH(10, -20, 1, 1), VecXY(0, 20), wall, res{0.45, VecXY(0, -1), true}},
+		{RectXYWH(0, 20, 1, 1), VecXY(20, -20), wall, res{0.5, VecXY(0, 1), true}},
+		{RectXYWH(0, 25, 1, 1), VecXY(20, -20), wall, res{}},
+		// Sliding along the wall.
+		{RectXYWH(9, 0, 1, 1), VecXY(0, 5), wall, res{}},
+		// Touching and moving in.
+		{RectXYWH(9, 0, 1, 1), VecXY(5, 0), wall, res{0, VecXY(-1, 0), true}},
+		// Already overlapping.
+		{RectXYWH(9.5, 0, 1, 1), VecXY(5, 0), wall, res{0, VecXY(-1, 0), true}},
+		{RectXYWH(0, 0, 1, 1), VecXY(0, 0), wall, res{}},
+	}
+
+	for i, c := range cases {
+		gotT, normal, hit := c.r.SweepRect(c.vel, c.target)
+		if hit != c.want.hit || (hit && (!fEqual(gotT, c.want.t) || !normal.Equals(c.want.normal, e))) {
+			t.Errorf("case %d: got %f, %s, %v, want %v", i, gotT, normal, hit, c.want)
+		}
+	}
+}
+
+func TestRectSweepCircle(t *testing.T) {
+	type res struct {
+		t      float64
+		normal Vec
+		hit    bool
+	}
+	cases := []struct {
+		r    Rect
+		vel  Vec
+		c    Circle
+		want res
+	}{
+		{RectXYWH(0, 0, 2, 2), VecXY(10, 0), CircleXYR(8, 1, 1), res{0.5, VecXY(-1, 0), true}},
+		{RectXYWH(0, 0, 2, 2), VecXY(10, 0), CircleXYR(8, 4, 1), res{}},
+		{RectXYWH(0, 0, 2, 2), VecXY(10, 0), CircleXYR(8, 3, 1), res{}},
+		// The Circle starts inside a corner of the Rect grown by its radius.
+		{RectXYWH(0, 0, 10, 10), VecXY(-1, -1), CircleXYR(-0.8, -0.8, 1),
+			res{0.8 - math.Sqrt2/2, VecXY(1, 1).Normalized(), true}},
+	}
+
+	for i, c := range cases {
+		gotT, normal, hit := c.r.SweepCircle(c.vel, c.c)
+		if hit != c.want.hit || (hit && (!fEqual(gotT, c.want.t) || !normal.Equals(c.want.normal, e))) {
+			t.Errorf("case %d: got %f, %s, %v, want %v", i, gotT, normal, hit, c.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// Segment is a 2-D line segment between points A and B. Many of the methods of Segment
+// describe positions along it with a value t, where t=0 is A and t=1 is B.
+type Segment struct {
+	A, B Vec
+}
+
+func (s Segment) String() string {
+	return fmt.Sprintf("Segment(%s, %s)", s.A, s.B)
+}
+
+// SegmentXY creates a Segment from (x1, y1) to (x2, y2).
+func SegmentXY(x1, y1, x2, y2 float64) Segment {
+	return Segment{A: Vec{X: x1, Y: y1}, B: Vec{X: x2, Y: y2}}
+}
+
+// Equals returns true if the corresponding end points of the Segments are within error e.
+func (s Segment) Equals(other Segment, e float64) bool {
+	return s.A.Equals(other.A, e) && s.B.Equals(other.B, e)
+}
+
+// Len returns the length of the Segment.
+func (s Segment) Len() float64 {
+	return s.A.Dist(s.B)
+}
+
+// Len2 returns the length of the Segment squared.
+func (s Segment) Len2() float64 {
+	return s.A.Dist2(s.B)
+}
+
+// Mid returns the point half way between A and B.
+func (s Segment) Mid() Vec {
+	return LerpVec(s.A, s.B, 0.5)
+}
+
+// At returns the point that is fraction t of the way from A to B.
+func (s Segment) At(t float64) Vec {
+	return LerpVec(s.A, s.B, t)
+}
+
+// BoundingRect returns the smallest Rect that surrounds the Segment.
+func (s Segment) BoundingRect() Rect {
+	return RectCorners(math.Min(s.A.X, s.B.X), math.Min(s.A.Y, s.B.Y),
+		math.Max(s.A.X, s.B.X), math.Max(s.A.Y, s.B.Y))
+}
+
+// ClosestPoint returns the point on the Segment that is closest to v.
+func (s Segment) ClosestPoint(v Vec) Vec {
+	return s.At(s.closestT(v))
+}
+
+// closestT returns the t value of the point on the Segment that is closest to v.
+func (s Segment) closestT(v Vec) float64 {
+	ab := s.B.Minus(s.A)
+	len2 := ab.Len2()
+	if len2 == 0 {
+		return 0
+	}
+	return Clamp(v.Minus(s.A).Dot(ab)/len2, 0, 1)
+}
+
+// DistPoint returns the shortest distance between the Segment and v.
+func (s Segment) DistPoint(v Vec) float64 {
+	return s.ClosestPoint(v).Dist(v)
+}
+
+// DistSegment returns the shortest distance between the two Segments, which is 0 if they
+// intersect.
+func (s Segment) DistSegment(other Segment) float64 {
+	if _, hit := s.IntersectSegment(other); hit {
+		return 0
+	}
+	return math.Min(
+		math.Min(s.DistPoint(other.A), s.DistPoint(other.B)),
+		math.Min(other.DistPoint(s.A), other.DistPoint(s.B)),
+	)
+}
+
+// IntersectSegment tests whether the two Segments intersect. If they cross at a single
+// point then overlap.A and overlap.B are both that point. If they are collinear and overlap
+// then overlap is the shared part of the Segments, in the same direction as s. If hit is
+// false then overlap is undefined.
+func (s Segment) IntersectSegment(other Segment) (overlap Segment, hit bool) {
+	// https://stackoverflow.com/a/565282
+	r := s.B.Minus(s.A)
+	q := other.B.Minus(other.A)
+	if r.Len2() == 0 {
+		if q.Len2() == 0 {
+			return Segment{A: s.A, B: s.A}, s.A == other.A
+		}
+		return other.IntersectSegment(s)
+	}
+
+	ao := other.A.Minus(s.A)
+	denom := r.Cross(q)
+	if denom == 0 {
+		if ao.Cross(r) != 0 {
+			// Parallel but not collinear.
+			return
+		}
+		t0 := ao.Dot(r) / r.Len2()
+		t1 := t0 + q.Dot(r)/r.Len2()
+		tMin := math.Max(math.Min(t0, t1), 0)
+		tMax := math.Min(math.Max(t0, t1), 1)
+		if tMin > tMax {
+			return
+		}
+		return Segment{A: s.At(tMin), B: s.At(tMax)}, true
+	}
+
+	t := ao.Cross(q) / denom
+	u := ao.Cross(r) / denom
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return
+	}
+	p := s.At(t)
+	return Segment{A: p, B: p}, true
+}
+
+// IntersectRay tests whether the Ray hits the Segment. It returns the position t along the
+// Segment where the Ray hits. Unlike Ray.IntersectSegment, hits behind the Ray's origin do
+// not count. If the Ray is parallel to the Segment then hit is false. If hit is false then t
+// is undefined.
+func (s Segment) IntersectRay(r Ray) (t float64, hit bool) {
+	ab := s.B.Minus(s.A)
+	denom := r.Direction.Cross(ab)
+	if denom == 0 {
+		return
+	}
+	ao := s.A.Minus(r.Origin)
+	// Distance along the Ray in units of r.Direction's length.
+	rayT := ao.Cross(ab) / denom
+	t = ao.Cross(r.Direction) / denom
+	return t, rayT >= 0 && t >= 0 && t <= 1
+}
+
+// IntersectCircle tests whether the Segment intersects the Circle. It returns the range
+// [tMin, tMax] of the Segment that is inside the Circle. If hit is false then tMin and tMax
+// are undefined. Use Segment.At to get the actual points as a Vec.
+func (s Segment) IntersectCircle(c Circle) (tMin, tMax float64, hit bool) {
+	ab := s.B.Minus(s.A)
+	fromCenter := s.A.Minus(c.Pos())
+	a := ab.Len2()
+	if a == 0 {
+		return 0, 0, c.CollidePoint(s.A.XY())
+	}
+	b := 2 * fromCenter.Dot(ab)
+	cc := fromCenter.Len2() - c.R*c.R
+	disc := b*b - 4*a*cc
+	if disc <= 0 {
+		return
+	}
+	sqrtDisc := math.Sqrt(disc)
+	tMin = math.Max((-b-sqrtDisc)/(2*a), 0)
+	tMax = math.Min((-b+sqrtDisc)/(2*a), 1)
+	return tMin, tMax, tMin <= tMax
+}
+
+// IntersectRect tests whether the Segment intersects the Rect. It returns the range
+// [tMin, tMax] of the Segment that is inside the Rect. If hit is false then tMin and tMax
+// are undefined. Use Segment.At to get the actual points as a Vec.
+func (s Segment) IntersectRect(r Rect) (tMin, tMax float64, hit bool) {
+	r.Normalize()
+	ab := s.B.Minus(s.A)
+	tMin, tMax = 0, 1
+	clip := func(start, d, min, max float64) bool {
+		if d == 0 {
+			return start >= min && start <= max
+		}
+		t1 := (min - start) / d
+		t2 := (max - start) / d
+		tMin = math.Max(tMin, math.Min(t1, t2))
+		tMax = math.Min(tMax, math.Max(t1, t2))
+		return true
+	}
+	if !clip(s.A.X, ab.X, r.Left(), r.Right()) || !clip(s.A.Y, ab.Y, r.Top(), r.Bottom()) {
+		return 0, 0, false
+	}
+	return tMin, tMax, tMin <= tMax
+}
@@ -0,0 +1,200 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSegmentString(t *testing.T) {
+	s := SegmentXY(1.2, -3.4, 5, 6)
+	got := s.String()
+	want := "Segment(Vec(1.2, -3.4), Vec(5, 6))"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestSegmentBasics(t *testing.T) {
+	s := SegmentXY(1, 5, 4, 1)
+	check(t, "len", s.Len, 5)
+	check(t, "len2", s.Len2, 25)
+	checkVec(t, "mid", s.Mid, VecXY(2.5, 3))
+	checkVec(t, "at", func() Vec { return s.At(0.25) }, VecXY(1.75, 4))
+	if got, want := s.BoundingRect(), RectXYWH(1, 1, 3, 4); got != want {
+		t.Errorf("bounding rect: got %s, want %s", got, want)
+	}
+	if !s.Equals(Segment{VecXY(1, 5), VecXY(4, 1)}, e) {
+		t.Errorf("%s should equal itself", s)
+	}
+	if s.Equals(Segment{VecXY(4, 1), VecXY(1, 5)}, e) {
+		t.Errorf("%s should not equal its reverse", s)
+	}
+}
+
+func TestSegmentClosestPoint(t *testing.T) {
+	cases := []struct {
+		s    Segment
+		v    Vec
+		want Vec
+		dist float64
+	}{
+		{SegmentXY(0, 0, 4, 0), VecXY(2, 3), VecXY(2, 0), 3},
+		{SegmentXY(0, 0, 4, 0), VecXY(-3, 4), VecXY(0, 0), 5},
+		{SegmentXY(0, 0, 4, 0), VecXY(7, -4), VecXY(4, 0), 5},
+		{SegmentXY(0, 0, 4, 4), VecXY(4, 0), VecXY(2, 2), 2 * math.Sqrt2},
+		{SegmentXY(1, 1, 1, 1), VecXY(4, 5), VecXY(1, 1), 5},
+	}
+
+	for i, c := range cases {
+		got := c.s.ClosestPoint(c.v)
+		if !got.Equals(c.want, e) {
+			t.Errorf("case %d: got %s, want %s", i, got, c.want)
+		}
+		dist := c.s.DistPoint(c.v)
+		if !fEqual(dist, c.dist) {
+			t.Errorf("case %d: got dist %f, want %f", i, dist, c.dist)
+		}
+	}
+}
+
+func TestSegmentIntersectSegment(t *testing.T) {
+	type res struct {
+		overlap Segment
+		hit     bool
+	}
+	cases := []struct {
+		s1, s2 Segment
+		want   res
+	}{
+		// Crossing
+		{SegmentXY(0, 0, 4, 4), SegmentXY(0, 4, 4, 0), res{SegmentXY(2, 2, 2, 2), true}},
+		{SegmentXY(0, 0, 4, 0), SegmentXY(4, 0, 4, 4), res{SegmentXY(4, 0, 4, 0), true}},
+		{SegmentXY(0, 0, 4, 0), SegmentXY(2, 0, 2, 4), res{SegmentXY(2, 0, 2, 0), true}},
+		// Not crossing
+		{SegmentXY(0, 0, 4, 4), SegmentXY(0, 4, 1, 3), res{}},
+		{SegmentXY(0, 0, 4, 0), SegmentXY(2, 1, 2, 4), res{}},
+		// Parallel
+		{SegmentXY(0, 0, 4, 0), SegmentXY(0, 1, 4, 1), res{}},
+		// Collinear
+		{SegmentXY(0, 0, 4, 0), SegmentXY(2, 0, 6, 0), res{SegmentXY(2, 0, 4, 0), true}},
+		{SegmentXY(0, 0, 4, 0), SegmentXY(6, 0, 2, 0), res{SegmentXY(2, 0, 4, 0), true}},
+		{SegmentXY(4, 0, 0, 0), SegmentXY(2, 0, 6, 0), res{SegmentXY(4, 0, 2, 0), true}},
+		{SegmentXY(0, 0, 4, 4), SegmentXY(1, 1, 2, 2), res{SegmentXY(1, 1, 2, 2), true}},
+		{SegmentXY(0, 0, 4, 0), SegmentXY(4, 0, 6, 0), res{SegmentXY(4, 0, 4, 0), true}},
+		{SegmentXY(0, 0, 4, 0), SegmentXY(5, 0, 6, 0), res{}},
+		// Zero length
+		{SegmentXY(2, 0, 2, 0), SegmentXY(0, 0, 4, 0), res{SegmentXY(2, 0, 2, 0), true}},
+		{SegmentXY(0, 0, 4, 0), SegmentXY(2, 0, 2, 0), res{SegmentXY(2, 0, 2, 0), true}},
+		{SegmentXY(2, 1, 2, 1), SegmentXY(0, 0, 4, 0), res{}},
+		{SegmentXY(2, 1, 2, 1), SegmentXY(2, 1, 2, 1), res{SegmentXY(2, 1, 2, 1), true}},
+	}
+
+	for i, c := range cases {
+		overlap, hit := c.s1.IntersectSegment(c.s2)
+		if hit != c.want.hit || (hit && !overlap.Equals(c.want.overlap, e)) {
+			t.Errorf("case %d: got %s, %v, want %s, %v", i, overlap, hit, c.want.overlap, c.want.hit)
+		}
+	}
+}
+
+func TestSegmentDistSegment(t *testing.T) {
+	cases := []struct {
+		s1, s2 Segment
+		want   float64
+	}{
+		{SegmentXY(0, 0, 4, 4), SegmentXY(0, 4, 4, 0), 0},
+		{SegmentXY(0, 0, 4, 0), SegmentXY(0, 1, 4, 1), 1},
+		{SegmentXY(0, 0, 4, 0), SegmentXY(2, 1, 2, 4), 1},
+		{SegmentXY(0, 0, 4, 0), SegmentXY(7, 4, 9, 8), 5},
+	}
+
+	for i, c := range cases {
+		got := c.s1.DistSegment(c.s2)
+		if !fEqual(got, c.want) {
+			t.Errorf("case %d: got %f, want %f", i, got, c.want)
+		}
+	}
+}
+
+func TestSegmentIntersectRay(t *testing.T) {
+	type res struct {
+		t   float64
+		hit bool
+	}
+	s := SegmentXY(0, -2, 0, 2)
+	cases := []struct {
+		r    Ray
+		want res
+	}{
+		{Ray{VecXY(-2, 0), VecXY(1, 0)}, res{0.5, true}},
+		{Ray{VecXY(-2, 0), VecXY(5, 0)}, res{0.5, true}},
+		{Ray{VecXY(-2, 1), VecXY(1, 0)}, res{0.75, true}},
+		{Ray{VecXY(-2, 0), VecXY(1, 1)}, res{1, true}},
+		{Ray{VecXY(-2, 0), VecXY(1, 2)}, res{}},
+		{Ray{VecXY(-2, 0), VecXY(-1, 0)}, res{}},
+		{Ray{VecXY(-2, 0), VecXY(0, 1)}, res{}},
+	}
+
+	for i, c := range cases {
+		gotT, hit := s.IntersectRay(c.r)
+		if hit != c.want.hit || (hit && !fEqual(gotT, c.want.t)) {
+			t.Errorf("case %d: got %f, %v, want %v", i, gotT, hit, c.want)
+		}
+	}
+}
+
+func TestSegmentIntersectCircle(t *testing.T) {
+	type res struct {
+		tMin, tMax float64
+		hit        bool
+	}
+	circ := CircleXYR(0, 0, 2)
+	cases := []struct {
+		s    Segment
+		want res
+	}{
+		{SegmentXY(-4, 0, 4, 0), res{0.25, 0.75, true}},
+		{SegmentXY(0, 0, 4, 0), res{0, 0.5, true}},
+		{SegmentXY(-1, 0, 1, 0), res{0, 1, true}},
+		{SegmentXY(-4, 2, 4, 2), res{}},
+		{SegmentXY(-4, 0, -3, 0), res{}},
+		{SegmentXY(1, 1, 1, 1), res{0, 0, true}},
+		{SegmentXY(3, 1, 3, 1), res{}},
+	}
+
+	for i, c := range cases {
+		tMin, tMax, hit := c.s.IntersectCircle(circ)
+		if hit != c.want.hit || (hit && (!fEqual(tMin, c.want.tMin) || !fEqual(tMax, c.want.tMax))) {
+			t.Errorf("case %d: got %f, %f, %v, want %v", i, tMin, tMax, hit, c.want)
+		}
+	}
+}
+
+func TestSegmentIntersectRect(t *testing.T) {
+	type res struct {
+		tMin, tMax float64
+		hit        bool
+	}
+	rect := RectXYWH(0, 0, 4, 2)
+	cases := []struct {
+		s    Segment
+		r    Rect
+		want res
+	}{
+		{SegmentXY(-4, 1, 8, 1), rect, res{1.0 / 3, 2.0 / 3, true}},
+		{SegmentXY(8, 1, -4, 1), rect, res{1.0 / 3, 2.0 / 3, true}},
+		{SegmentXY(2, 1, 8, 1), rect, res{0, 1.0 / 3, true}},
+		{SegmentXY(1, 1, 2, 1), rect, res{0, 1, true}},
+		{SegmentXY(-2, -1, 2, 3), rect, res{0.5, 0.75, true}},
+		{SegmentXY(-4, 3, 8, 3), rect, res{}},
+		{SegmentXY(1, -4, 1, -1), rect, res{}},
+		{SegmentXY(-4, 1, 8, 1), RectXYWH(4, 2, -4, -2), res{1.0 / 3, 2.0 / 3, true}},
+	}
+
+	for i, c := range cases {
+		tMin, tMax, hit := c.s.IntersectRect(c.r)
+		if hit != c.want.hit || (hit && (!fEqual(tMin, c.want.tMin) || !fEqual(tMax, c.want.tMax))) {
+			t.Errorf("case %d: got %f, %f, %v, want %v", i, tMin, tMax, hit, c.want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package geo
+
+// shape is either a Rect or a Circle. It is what the spatial indexes store so that they can
+// confirm candidates with the exact collision functions of each type.
+type shape struct {
+	rect     Rect
+	circle   Circle
+	isCircle bool
+}
+
+func rectShape(r Rect) shape {
+	return shape{rect: r.Normalized()}
+}
+
+func circleShape(c Circle) shape {
+	return shape{circle: c.Normalized(), isCircle: true}
+}
+
+// bounds returns the smallest Rect that surrounds the shape.
+func (s shape) bounds() Rect {
+	if s.isCircle {
+		return s.circle.BoundingRect()
+	}
+	return s.rect
+}
+
+func (s shape) collidePoint(x, y float64) bool {
+	if s.isCircle {
+		return s.circle.CollidePoint(x, y)
+	}
+	return s.rect.CollidePoint(x, y)
+}
+
+func (s shape) collideRect(r Rect) bool {
+	if s.isCircle {
+		return s.circle.CollideRect(r)
+	}
+	return s.rect.CollideRect(r)
+}
+
+func (s shape) collideCircle(c Circle) bool {
+	if s.isCircle {
+		return s.circle.CollideCircle(c)
+	}
+	return s.rect.CollideCircle(c)
+}
+
+func (s shape) collideShape(other shape) bool {
+	if other.isCircle {
+		return s.collideCircle(other.circle)
+	}
+	return s.collideRect(other.rect)
+}
+
+// intersectRay is like Ray.IntersectRect or Ray.IntersectCircle, except that hit is only
+// true if the shape is in front of or contains the Ray's origin.
+func (s shape) intersectRay(r Ray) (tMin, tMax float64, hit bool) {
+	if s.isCircle {
+		tMin, tMax, hit = r.IntersectCircle(s.circle)
+	} else {
+		tMin, tMax, hit = r.IntersectRect(s.rect)
+	}
+	return tMin, tMax, hit && tMax >= 0
+}
@@ -0,0 +1,237 @@
+package geo
+
+import "math"
+
+// Implementation based on Stefan Gustavson's "Simplex noise demystified"
+// http://staffwww.itn.liu.se/~stegu/simplexnoise/simplexnoise.pdf
+
+var grad3 = [][3]float64{
+	{1, 1, 0}, {-1, 1, 0}, {1, -1, 0}, {-1, -1, 0},
+	{1, 0, 1}, {-1, 0, 1}, {1, 0, -1}, {-1, 0, -1},
+	{0, 1, 1}, {0, -1, 1}, {0, 1, -1}, {0, -1, -1},
+}
+
+var grad4 = [][4]float64{
+	{0, 1, 1, 1}, {0, 1, 1, -1}, {0, 1, -1, 1}, {0, 1, -1, -1},
+	{0, -1, 1, 1}, {0, -1, 1, -1}, {0, -1, -1, 1}, {0, -1, -1, -1},
+	{1, 0, 1, 1}, {1, 0, 1, -1}, {1, 0, -1, 1}, {1, 0, -1, -1},
+	{-1, 0, 1, 1}, {-1, 0, 1, -1}, {-1, 0, -1, 1}, {-1, 0, -1, -1},
+	{1, 1, 0, 1}, {1, 1, 0, -1}, {1, -1, 0, 1}, {1, -1, 0, -1},
+	{-1, 1, 0, 1}, {-1, 1, 0, -1}, {-1, -1, 0, 1}, {-1, -1, 0, -1},
+	{1, 1, 1, 0}, {1, 1, -1, 0}, {1, -1, 1, 0}, {1, -1, -1, 0},
+	{-1, 1, 1, 0}, {-1, 1, -1, 0}, {-1, -1, 1, 0}, {-1, -1, -1, 0},
+}
+
+// Skewing and unskewing factors for each dimension.
+var (
+	f2 = 0.5 * (math.Sqrt(3) - 1)
+	g2 = (3 - math.Sqrt(3)) / 6
+	f3 = 1.0 / 3
+	g3 = 1.0 / 6
+	f4 = (math.Sqrt(5) - 1) / 4
+	g4 = (5 - math.Sqrt(5)) / 20
+)
+
+// Simplex2 implements 2-D simplex noise. It has fewer directional artifacts than Perlin and
+// is cheaper to compute. It returns values between 0 and 1.
+func Simplex2(x, y float64) float64 {
+	return defaultNoise.Simplex2(x, y)
+}
+
+// Simplex2 is like the package level Simplex2 but uses n's permutation.
+func (n *Noise) Simplex2(x, y float64) float64 {
+	// Skew the input space to find which simplex cell we're in.
+	s := (x + y) * f2
+	i := math.Floor(x + s)
+	j := math.Floor(y + s)
+	t := (i + j) * g2
+	// The distances from the cell origin.
+	x0 := x - (i - t)
+	y0 := y - (j - t)
+
+	// Find which of the two triangles we're in.
+	i1, j1 := 0, 1
+	if x0 > y0 {
+		i1, j1 = 1, 0
+	}
+
+	x1 := x0 - float64(i1) + g2
+	y1 := y0 - float64(j1) + g2
+	x2 := x0 - 1 + 2*g2
+	y2 := y0 - 1 + 2*g2
+
+	ii := int(i) & 255
+	jj := int(j) & 255
+	sum := simplexCorner2(n.p[ii+n.p[jj]], x0, y0) +
+		simplexCorner2(n.p[ii+i1+n.p[jj+j1]], x1, y1) +
+		simplexCorner2(n.p[ii+1+n.p[jj+1]], x2, y2)
+
+	// Scale to roughly -1 to +1, then change to 0 to +1.
+	return Clamp((70*sum+1)/2, 0, 1)
+}
+
+func simplexCorner2(hash int, x, y float64) float64 {
+	t := 0.5 - x*x - y*y
+	if t < 0 {
+		return 0
+	}
+	g := grad3[hash%12]
+	t *= t
+	return t * t * (g[0]*x + g[1]*y)
+}
+
+// Simplex3 implements 3-D simplex noise. It returns values between 0 and 1.
+func Simplex3(x, y, z float64) float64 {
+	return defaultNoise.Simplex3(x, y, z)
+}
+
+// Simplex3 is like the package level Simplex3 but uses n's permutation.
+func (n *Noise) Simplex3(x, y, z float64) float64 {
+	s := (x + y + z) * f3
+	i := math.Floor(x + s)
+	j := math.Floor(y + s)
+	k := math.Floor(z + s)
+	t := (i + j + k) * g3
+	x0 := x - (i - t)
+	y0 := y - (j - t)
+	z0 := z - (k - t)
+
+	// Find which of the six tetrahedrons we're in, given by the offsets of the second and
+	// third corners.
+	var i1, j1, k1, i2, j2, k2 int
+	if x0 >= y0 {
+		if y0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 1, 0
+		} else if x0 >= z0 {
+			i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 0, 1
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 1, 0, 1
+		}
+	} else {
+		if y0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 0, 1, 0, 1, 1
+		} else if x0 < z0 {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 0, 1, 1
+		} else {
+			i1, j1, k1, i2, j2, k2 = 0, 1, 0, 1, 1, 0
+		}
+	}
+
+	x1 := x0 - float64(i1) + g3
+	y1 := y0 - float64(j1) + g3
+	z1 := z0 - float64(k1) + g3
+	x2 := x0 - float64(i2) + 2*g3
+	y2 := y0 - float64(j2) + 2*g3
+	z2 := z0 - float64(k2) + 2*g3
+	x3 := x0 - 1 + 3*g3
+	y3 := y0 - 1 + 3*g3
+	z3 := z0 - 1 + 3*g3
+
+	ii := int(i) & 255
+	jj := int(j) & 255
+	kk := int(k) & 255
+	sum := simplexCorner3(n.p[ii+n.p[jj+n.p[kk]]], x0, y0, z0) +
+		simplexCorner3(n.p[ii+i1+n.p[jj+j1+n.p[kk+k1]]], x1, y1, z1) +
+		simplexCorner3(n.p[ii+i2+n.p[jj+j2+n.p[kk+k2]]], x2, y2, z2) +
+		simplexCorner3(n.p[ii+1+n.p[jj+1+n.p[kk+1]]], x3, y3, z3)
+
+	return Clamp((32*sum+1)/2, 0, 1)
+}
+
+func simplexCorner3(hash int, x, y, z float64) float64 {
+	t := 0.6 - x*x - y*y - z*z
+	if t < 0 {
+		return 0
+	}
+	g := grad3[hash%12]
+	t *= t
+	return t * t * (g[0]*x + g[1]*y + g[2]*z)
+}
+
+// Simplex4 implements 4-D simplex noise. A common use of the 4th dimension is animating
+// 3-D noise over time. It returns values between 0 and 1.
+func Simplex4(x, y, z, w float64) float64 {
+	return defaultNoise.Simplex4(x, y, z, w)
+}
+
+// Simplex4 is like the package level Simplex4 but uses n's permutation.
+func (n *Noise) Simplex4(x, y, z, w float64) float64 {
+	s := (x + y + z + w) * f4
+	i := math.Floor(x + s)
+	j := math.Floor(y + s)
+	k := math.Floor(z + s)
+	l := math.Floor(w + s)
+	t := (i + j + k + l) * g4
+	x0 := x - (i - t)
+	y0 := y - (j - t)
+	z0 := z - (k - t)
+	w0 := w - (l - t)
+
+	// Rank the coordinates by magnitude to find which of the 24 simplices we're in. The
+	// corners are visited by stepping along the largest coordinate first.
+	var rankX, rankY, rankZ, rankW int
+	if x0 > y0 {
+		rankX++
+	} else {
+		rankY++
+	}
+	if x0 > z0 {
+		rankX++
+	} else {
+		rankZ++
+	}
+	if x0 > w0 {
+		rankX++
+	} else {
+		rankW++
+	}
+	if y0 > z0 {
+		rankY++
+	} else {
+		rankZ++
+	}
+	if y0 > w0 {
+		rankY++
+	} else {
+		rankW++
+	}
+	if z0 > w0 {
+		rankZ++
+	} else {
+		rankW++
+	}
+
+	ii := int(i) & 255
+	jj := int(j) & 255
+	kk := int(k) & 255
+	ll := int(l) & 255
+	sum := 0.0
+	for c := 0; c <= 4; c++ {
+		// The offset of corner c along each axis. Corner 0 is the cell origin and corner 4 is
+		// the opposite corner.
+		oi, oj, ok, ol := rankStep(rankX, c), rankStep(rankY, c), rankStep(rankZ, c), rankStep(rankW, c)
+		g := float64(c) * g4
+		sum += simplexCorner4(n.p[ii+oi+n.p[jj+oj+n.p[kk+ok+n.p[ll+ol]]]],
+			x0-float64(oi)+g, y0-float64(oj)+g, z0-float64(ok)+g, w0-float64(ol)+g)
+	}
+
+	return Clamp((27*sum+1)/2, 0, 1)
+}
+
+// rankStep returns 1 if the axis with the given rank has been stepped along by corner c.
+func rankStep(rank, c int) int {
+	if rank >= 4-c {
+		return 1
+	}
+	return 0
+}
+
+func simplexCorner4(hash int, x, y, z, w float64) float64 {
+	t := 0.6 - x*x - y*y - z*z - w*w
+	if t < 0 {
+		return 0
+	}
+	g := grad4[hash%32]
+	t *= t
+	return t * t * (g[0]*x + g[1]*y + g[2]*z + g[3]*w)
+}
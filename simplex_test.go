@@ -0,0 +1,61 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// checkNoise checks that fn stays between 0 and 1, actually varies, and is continuous.
+func checkNoise(t *testing.T, name string, fn func(v []float64) float64, dims int) {
+	min, max, sum := 1.0, 0.0, 0.0
+	const n = 2000
+	for i := 0; i < n; i++ {
+		v := make([]float64, dims)
+		for d := range v {
+			v[d] = rand.Float64()*200 - 100
+		}
+		got := fn(v)
+		if got < 0 || got > 1 {
+			t.Errorf("%s: out of range: got %f at %v", name, got, v)
+		}
+		min, max, sum = math.Min(min, got), math.Max(max, got), sum+got
+
+		// A tiny step can only change the value a little.
+		w := append([]float64{}, v...)
+		w[rand.Intn(dims)] += 1e-4
+		if diff := math.Abs(fn(w) - got); diff > 1e-2 {
+			t.Errorf("%s: discontinuous: %v to %v changed by %f", name, v, w, diff)
+		}
+	}
+	if min > 0.2 || max < 0.8 {
+		t.Errorf("%s: range too small: got %f to %f", name, min, max)
+	}
+	if mean := sum / n; math.Abs(mean-0.5) > 0.05 {
+		t.Errorf("%s: mean: got %f, want about 0.5", name, mean)
+	}
+}
+
+func TestSimplex2(t *testing.T) {
+	checkNoise(t, "simplex2", func(v []float64) float64 { return Simplex2(v[0], v[1]) }, 2)
+	if got := Simplex2(0, 0); got != 0.5 {
+		t.Errorf("origin: got %f, want %f", got, 0.5)
+	}
+	if a, b := Simplex2(1.5, 2.5), Simplex2(1.5, 2.5); a != b {
+		t.Errorf("not deterministic: got %f and %f", a, b)
+	}
+}
+
+func TestSimplex3(t *testing.T) {
+	checkNoise(t, "simplex3", func(v []float64) float64 { return Simplex3(v[0], v[1], v[2]) }, 3)
+	if got := Simplex3(0, 0, 0); got != 0.5 {
+		t.Errorf("origin: got %f, want %f", got, 0.5)
+	}
+}
+
+func TestSimplex4(t *testing.T) {
+	checkNoise(t, "simplex4", func(v []float64) float64 { return Simplex4(v[0], v[1], v[2], v[3]) }, 4)
+	if got := Simplex4(0, 0, 0, 0); got != 0.5 {
+		t.Errorf("origin: got %f, want %f", got, 0.5)
+	}
+}
@@ -0,0 +1,280 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// Pair is a pair of indices of colliding shapes, with A < B.
+type Pair struct {
+	A, B int
+}
+
+// sortPairs sorts the pairs by A and then B.
+func sortPairs(pairs []Pair) {
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+}
+
+// SpatialHash is a spatial index of Rects and Circles, each identified by an index chosen by
+// the caller, that sorts them into a uniform grid of square cells. It works best when the
+// shapes are of similar size and the cell size is a bit larger than a typical shape. Shapes
+// that would cover more than a few hundred cells are kept in a separate list that every
+// query checks, so a handful of very large shapes is fine but many of them are slow.
+type SpatialHash struct {
+	cellSize float64
+	cells    map[shCell][]*shItem
+	items    map[int]*shItem
+	// big holds the shapes that cover too many cells to add to each of them.
+	big map[int]*shItem
+}
+
+// shMaxCells is the most cells that a shape is added to before it is treated as big.
+const shMaxCells = 256
+
+type shCell struct {
+	x, y int
+}
+
+type shItem struct {
+	i     int
+	shape shape
+	// The range of cells the shape is in, inclusive. They aren't used if big is true.
+	min, max shCell
+	big      bool
+}
+
+// NewSpatialHash creates an empty SpatialHash whose cells are cellSize wide and tall. It
+// panics if cellSize isn't positive.
+func NewSpatialHash(cellSize float64) *SpatialHash {
+	if !(cellSize > 0) || math.IsInf(cellSize, 1) {
+		panic("geo: NewSpatialHash: cellSize must be positive and finite")
+	}
+	return &SpatialHash{
+		cellSize: cellSize,
+		cells:    make(map[shCell][]*shItem),
+		items:    make(map[int]*shItem),
+		big:      make(map[int]*shItem),
+	}
+}
+
+// Len returns the number of shapes in the SpatialHash.
+func (h *SpatialHash) Len() int {
+	return len(h.items)
+}
+
+// InsertRect adds the Rect to the SpatialHash with the index i. If i is already in the
+// SpatialHash then it is moved instead.
+func (h *SpatialHash) InsertRect(i int, r Rect) {
+	h.move(i, rectShape(r))
+}
+
+// InsertCircle adds the Circle to the SpatialHash with the index i. If i is already in the
+// SpatialHash then it is moved instead.
+func (h *SpatialHash) InsertCircle(i int, c Circle) {
+	h.move(i, circleShape(c))
+}
+
+// MoveRect changes the shape with index i to the Rect. If i isn't in the SpatialHash then
+// it is inserted. Only the cells that the shape enters or leaves are updated, so calling
+// this every frame for shapes that move a little is cheap.
+func (h *SpatialHash) MoveRect(i int, r Rect) {
+	h.move(i, rectShape(r))
+}
+
+// MoveCircle changes the shape with index i to the Circle. If i isn't in the SpatialHash
+// then it is inserted. Only the cells that the shape enters or leaves are updated, so
+// calling this every frame for shapes that move a little is cheap.
+func (h *SpatialHash) MoveCircle(i int, c Circle) {
+	h.move(i, circleShape(c))
+}
+
+func (h *SpatialHash) move(i int, s shape) {
+	min, max, big := h.cellRange(s.bounds())
+	item, ok := h.items[i]
+	if !ok {
+		item = &shItem{i: i, shape: s, min: min, max: max, big: big}
+		h.items[i] = item
+		h.addToCells(item)
+		return
+	}
+	item.shape = s
+	if item.big == big && (big || item.min == min && item.max == max) {
+		return
+	}
+	h.removeFromCells(item)
+	item.min, item.max, item.big = min, max, big
+	h.addToCells(item)
+}
+
+// Remove removes the shape with index i and returns true, or returns false if i isn't in
+// the SpatialHash.
+func (h *SpatialHash) Remove(i int) bool {
+	item, ok := h.items[i]
+	if !ok {
+		return false
+	}
+	delete(h.items, i)
+	h.removeFromCells(item)
+	return true
+}
+
+// QueryRect returns a sorted list of indices of the shapes that collide with the Rect, or
+// an empty list if none.
+func (h *SpatialHash) QueryRect(r Rect) []int {
+	min, max, big := h.cellRange(r.Normalized())
+	return h.query(min, max, big, func(s shape) bool { return s.collideRect(r) })
+}
+
+// QueryCircle returns a sorted list of indices of the shapes that collide with the Circle,
+// or an empty list if none.
+func (h *SpatialHash) QueryCircle(c Circle) []int {
+	min, max, big := h.cellRange(c.Normalized().BoundingRect())
+	return h.query(min, max, big, func(s shape) bool { return s.collideCircle(c) })
+}
+
+// QueryPoint returns a sorted list of indices of the shapes that contain the point, or an
+// empty list if none.
+func (h *SpatialHash) QueryPoint(x, y float64) []int {
+	min, max, big := h.cellRange(Rect{X: x, Y: y})
+	return h.query(min, max, big, func(s shape) bool { return s.collidePoint(x, y) })
+}
+
+// Pairs returns every pair of shapes in the SpatialHash that collide, each pair exactly
+// once. The pairs are sorted by A and then B.
+func (h *SpatialHash) Pairs() []Pair {
+	pairs := make([]Pair, 0)
+	for cell, items := range h.cells {
+		for j, a := range items {
+			for _, b := range items[j+1:] {
+				// Two shapes can share many cells, so only report them from the first cell they
+				// share.
+				first := shCell{x: maxInt(a.min.x, b.min.x), y: maxInt(a.min.y, b.min.y)}
+				if cell != first || !a.shape.collideShape(b.shape) {
+					continue
+				}
+				pairs = append(pairs, newPair(a.i, b.i))
+			}
+		}
+	}
+	for _, a := range h.big {
+		for _, b := range h.items {
+			// Pairs of big shapes are only reported from the one with the lower index.
+			if b == a || b.big && b.i < a.i || !a.shape.collideShape(b.shape) {
+				continue
+			}
+			pairs = append(pairs, newPair(a.i, b.i))
+		}
+	}
+	sortPairs(pairs)
+	return pairs
+}
+
+// newPair returns the Pair of a and b in order.
+func newPair(a, b int) Pair {
+	if a < b {
+		return Pair{A: a, B: b}
+	}
+	return Pair{A: b, B: a}
+}
+
+func (h *SpatialHash) query(min, max shCell, big bool, match func(shape) bool) []int {
+	list := make([]int, 0)
+	if big {
+		// Checking every shape is quicker than walking that many cells.
+		for _, item := range h.items {
+			if match(item.shape) {
+				list = append(list, item.i)
+			}
+		}
+		sort.Ints(list)
+		return list
+	}
+	for _, item := range h.big {
+		if match(item.shape) {
+			list = append(list, item.i)
+		}
+	}
+	seen := make(map[int]bool)
+	for x := min.x; x <= max.x; x++ {
+		for y := min.y; y <= max.y; y++ {
+			for _, item := range h.cells[shCell{x: x, y: y}] {
+				if !seen[item.i] {
+					seen[item.i] = true
+					if match(item.shape) {
+						list = append(list, item.i)
+					}
+				}
+			}
+		}
+	}
+	sort.Ints(list)
+	return list
+}
+
+// cellRange returns the range of cells that bounds covers, or big as true if that is more
+// than shMaxCells, or bounds is too far away or not a number, in which case the range isn't
+// used.
+func (h *SpatialHash) cellRange(bounds Rect) (min, max shCell, big bool) {
+	left, top := math.Floor(bounds.Left()/h.cellSize), math.Floor(bounds.Top()/h.cellSize)
+	right, bottom := math.Floor(bounds.Right()/h.cellSize), math.Floor(bounds.Bottom()/h.cellSize)
+	// The comparisons are written so that NaNs fail them.
+	const limit = 1 << 40
+	inRange := func(f float64) bool { return f > -limit && f < limit }
+	if !inRange(left) || !inRange(top) || !inRange(right) || !inRange(bottom) ||
+		!((right-left+1)*(bottom-top+1) <= shMaxCells) {
+		return shCell{}, shCell{}, true
+	}
+	return shCell{x: int(left), y: int(top)}, shCell{x: int(right), y: int(bottom)}, false
+}
+
+func (h *SpatialHash) addToCells(item *shItem) {
+	if item.big {
+		h.big[item.i] = item
+		return
+	}
+	for x := item.min.x; x <= item.max.x; x++ {
+		for y := item.min.y; y <= item.max.y; y++ {
+			cell := shCell{x: x, y: y}
+			h.cells[cell] = append(h.cells[cell], item)
+		}
+	}
+}
+
+func (h *SpatialHash) removeFromCells(item *shItem) {
+	if item.big {
+		delete(h.big, item.i)
+		return
+	}
+	for x := item.min.x; x <= item.max.x; x++ {
+		for y := item.min.y; y <= item.max.y; y++ {
+			cell := shCell{x: x, y: y}
+			items := h.cells[cell]
+			for j, other := range items {
+				if other == item {
+					last := len(items) - 1
+					items[j] = items[last]
+					items[last] = nil
+					items = items[:last]
+					break
+				}
+			}
+			if len(items) == 0 {
+				delete(h.cells, cell)
+			} else {
+				h.cells[cell] = items
+			}
+		}
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
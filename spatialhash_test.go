@@ -0,0 +1,185 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func pairListEqual(a, b []Pair) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, p := range a {
+		if p != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func bruteRectPairs(rects []Rect) []Pair {
+	pairs := []Pair{}
+	for i, r := range rects {
+		for j := i + 1; j < len(rects); j++ {
+			if r.CollideRect(rects[j]) {
+				pairs = append(pairs, Pair{A: i, B: j})
+			}
+		}
+	}
+	return pairs
+}
+
+// checkRectPairs compares the pairs found by a spatial index holding rects against checking
+// every pair of Rects.
+func checkRectPairs(t *testing.T, stage string, got []Pair, rects []Rect) {
+	if want := bruteRectPairs(rects); !pairListEqual(got, want) {
+		t.Errorf("%s: pairs: got %v, want %v", stage, got, want)
+	}
+}
+
+func TestSpatialHashRects(t *testing.T) {
+	bounds := RectXYWH(-100, -100, 200, 200)
+	h := NewSpatialHash(10)
+	rects := make([]Rect, 200)
+	for i := range rects {
+		rects[i] = randRect(bounds, 15)
+		h.InsertRect(i, rects[i])
+	}
+	if h.Len() != len(rects) {
+		t.Errorf("len: got %d, want %d", h.Len(), len(rects))
+	}
+
+	checkRectPairs(t, "inserted", h.Pairs(), rects)
+	checkRectQueries(t, "inserted", h, rects, bounds)
+
+	for frame := 0; frame < 5; frame++ {
+		for i := range rects {
+			rects[i].Move(rand.Float64()*6-3, rand.Float64()*6-3)
+			h.MoveRect(i, rects[i])
+		}
+		checkRectPairs(t, "moved", h.Pairs(), rects)
+		checkRectQueries(t, "moved", h, rects, bounds)
+	}
+
+	removeRects(t, h, rects)
+	checkRectPairs(t, "removed", h.Pairs(), rects)
+	checkRectQueries(t, "removed", h, rects, bounds)
+
+	for i := range rects {
+		h.Remove(i)
+	}
+	if h.Len() != 0 || len(h.cells) != 0 {
+		t.Errorf("empty: got len %d, cells %d", h.Len(), len(h.cells))
+	}
+}
+
+func TestSpatialHashCircles(t *testing.T) {
+	bounds := RectXYWH(-100, -100, 200, 200)
+	h := NewSpatialHash(10)
+	circles := make([]Circle, 200)
+	for i := range circles {
+		circles[i] = randCircle(bounds, 8)
+		h.InsertCircle(i, circles[i])
+	}
+
+	wantPairs := []Pair{}
+	for i, c := range circles {
+		for j := i + 1; j < len(circles); j++ {
+			if c.CollideCircle(circles[j]) {
+				wantPairs = append(wantPairs, Pair{A: i, B: j})
+			}
+		}
+	}
+	if got := h.Pairs(); !pairListEqual(got, wantPairs) {
+		t.Errorf("pairs: got %v, want %v", got, wantPairs)
+	}
+
+	for trial := 0; trial < 50; trial++ {
+		r := randRect(bounds, 50)
+		if got, want := h.QueryRect(r), r.CollideCircleListAll(circles); !intListEqual(got, want) {
+			t.Errorf("rect %s: got %v, want %v", r, got, want)
+		}
+		c := randCircle(bounds, 30)
+		if got, want := h.QueryCircle(c), c.CollideCircleListAll(circles); !intListEqual(got, want) {
+			t.Errorf("circle %s: got %v, want %v", c, got, want)
+		}
+	}
+}
+
+func TestSpatialHashPairsMixed(t *testing.T) {
+	h := NewSpatialHash(5)
+	// Large shapes spanning many cells are still only reported once.
+	h.InsertRect(3, RectXYWH(0, 0, 50, 50))
+	h.InsertRect(1, RectXYWH(10, 10, 50, 50))
+	h.InsertCircle(0, CircleXYR(55, 55, 4))
+	h.InsertCircle(2, CircleXYR(100, 100, 4))
+	h.InsertCircle(4, CircleXYR(-4, 25, 4))
+
+	want := []Pair{{A: 0, B: 1}, {A: 1, B: 3}}
+	if got := h.Pairs(); !pairListEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSpatialHashBig(t *testing.T) {
+	h := NewSpatialHash(1)
+	rects := []Rect{
+		RectXYWH(0, 0, 2, 2),
+		RectXYWH(-1e9, -1e9, 2e9, 2e9),
+		RectXYWH(5, 5, 1, 1),
+		RectXYWH(-1e300, 3, 2e300, 1),
+		RectXYWH(-50, -50, 100, 100),
+		RectXYWH(math.NaN(), 0, 1, 1),
+	}
+	for i, r := range rects {
+		h.InsertRect(i, r)
+	}
+	if len(h.big) != 4 {
+		t.Errorf("big: got %d, want 4", len(h.big))
+	}
+	check := func(stage string) {
+		checkRectPairs(t, stage, h.Pairs(), rects)
+		for _, r := range []Rect{RectXYWH(0.5, 0.5, 1, 1), RectXYWH(-1e12, 4, 2e12, 2), RectXYWH(4, 4.5, 3, 0)} {
+			if got, want := h.QueryRect(r), r.CollideRectListAll(rects); !intListEqual(got, want) {
+				t.Errorf("%s: rect %s: got %v, want %v", stage, r, got, want)
+			}
+		}
+		want := []int{}
+		for i, r := range rects {
+			if r.CollidePoint(5.5, 3.5) {
+				want = append(want, i)
+			}
+		}
+		if got := h.QueryPoint(5.5, 3.5); !intListEqual(got, want) {
+			t.Errorf("%s: point: got %v, want %v", stage, got, want)
+		}
+	}
+	check("inserted")
+
+	// Shapes move between the cells and the big list.
+	rects[1], rects[2] = RectXYWH(1, 1, 3, 3), RectXYWH(0, 0, 1e6, 1)
+	h.MoveRect(1, rects[1])
+	h.MoveRect(2, rects[2])
+	check("moved")
+
+	for i := range rects {
+		h.Remove(i)
+	}
+	if h.Len() != 0 || len(h.cells) != 0 || len(h.big) != 0 {
+		t.Errorf("empty: got len %d, cells %d, big %d", h.Len(), len(h.cells), len(h.big))
+	}
+}
+
+func TestNewSpatialHashPanics(t *testing.T) {
+	for _, size := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("size %f: didn't panic", size)
+				}
+			}()
+			NewSpatialHash(size)
+		}()
+	}
+}
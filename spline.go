@@ -0,0 +1,252 @@
+package geo
+
+import (
+	"math"
+	"sort"
+)
+
+// Path is a smooth curve made of CubicBeziers joined end to end. Many of the methods of Path
+// describe positions along it with a value t, where t=0 is the start and t=1 is the end, and
+// each curve covers an equal part of t. For a constant speed use the methods that take a
+// distance along the Path instead.
+type Path struct {
+	curves []CubicBezier
+	// ends is the length of the Path at the end of each curve.
+	ends   []float64
+	closed bool
+}
+
+// NewPath creates a Path that follows each of the curves in turn. If closed is true then the
+// Path is treated as a loop, so t and distances wrap around instead of being clamped, and the
+// last curve should end where the first begins.
+func NewPath(closed bool, curves ...CubicBezier) *Path {
+	p := &Path{curves: curves, ends: make([]float64, len(curves)), closed: closed}
+	total := 0.0
+	for i, c := range curves {
+		total += c.Len()
+		p.ends[i] = total
+	}
+	return p
+}
+
+// CatmullRomType is the way a Catmull-Rom spline spaces its knots, which changes how tightly
+// it follows the points.
+type CatmullRomType int
+
+const (
+	// CatmullRomUniform spaces the knots evenly. It can overshoot and form loops and cusps
+	// when the points are unevenly spaced.
+	CatmullRomUniform CatmullRomType = iota
+	// CatmullRomCentripetal spaces the knots by the square root of the distance between
+	// points, which never forms loops or cusps within a curve.
+	CatmullRomCentripetal
+	// CatmullRomChordal spaces the knots by the distance between points, which follows the
+	// points the most loosely.
+	CatmullRomChordal
+)
+
+// alpha returns the power that the distance between points is raised to for spacing knots.
+func (c CatmullRomType) alpha() float64 {
+	switch c {
+	case CatmullRomCentripetal:
+		return 0.5
+	case CatmullRomChordal:
+		return 1
+	}
+	return 0
+}
+
+// NewCatmullRom creates a Path that passes through each of the points in order, following a
+// Catmull-Rom spline, such as for a patrol route through waypoints. If closed is true then
+// the Path continues from the last point back to the first.
+func NewCatmullRom(points []Vec, typ CatmullRomType, closed bool) *Path {
+	n := len(points)
+	if n < 2 {
+		return newPointPath(points, closed)
+	}
+
+	at := func(i int) Vec {
+		switch {
+		case closed:
+			return points[int(Mod(float64(i), float64(n)))]
+		case i < 0:
+			// Extend the ends by reflecting their neighbours.
+			return points[0].Times(2).Minus(points[1])
+		case i >= n:
+			return points[n-1].Times(2).Minus(points[n-2])
+		}
+		return points[i]
+	}
+
+	segments := n - 1
+	if closed {
+		segments = n
+	}
+	alpha := typ.alpha()
+	curves := make([]CubicBezier, segments)
+	for i := range curves {
+		curves[i] = catmullRomCurve(at(i-1), at(i), at(i+1), at(i+2), alpha)
+	}
+	return NewPath(closed, curves...)
+}
+
+// catmullRomCurve returns the CubicBezier that follows a Catmull-Rom spline from p1 to p2.
+func catmullRomCurve(p0, p1, p2, p3 Vec, alpha float64) CubicBezier {
+	if p1.Dist2(p2) == 0 {
+		return CubicBezier{p1, p1, p2, p2}
+	}
+	// Coincident points have no distance to space knots by, so they use the middle spacing.
+	d1 := math.Pow(p1.Dist(p2), alpha)
+	d0, d2 := math.Pow(p0.Dist(p1), alpha), math.Pow(p2.Dist(p3), alpha)
+	if p0.Dist2(p1) == 0 {
+		d0 = d1
+	}
+	if p2.Dist2(p3) == 0 {
+		d2 = d1
+	}
+
+	// The tangents of the spline at p1 and p2, scaled to the curve from p1 to p2.
+	m1 := p1.Minus(p0).DividedBy(d0).
+		Minus(p2.Minus(p0).DividedBy(d0 + d1)).
+		Plus(p2.Minus(p1).DividedBy(d1)).
+		Times(d1)
+	m2 := p2.Minus(p1).DividedBy(d1).
+		Minus(p3.Minus(p1).DividedBy(d1 + d2)).
+		Plus(p3.Minus(p2).DividedBy(d2)).
+		Times(d1)
+	return CubicBezier{p1, p1.Plus(m1.DividedBy(3)), p2.Minus(m2.DividedBy(3)), p2}
+}
+
+// NewBSpline creates a Path that follows a uniform cubic B-spline of the points. It is
+// smoother than a Catmull-Rom spline but only passes near the points rather than through
+// them. If closed is false then the Path starts at the first point and ends at the last. If
+// closed is true then the Path loops around all of the points.
+func NewBSpline(points []Vec, closed bool) *Path {
+	n := len(points)
+	if n < 2 {
+		return newPointPath(points, closed)
+	}
+
+	at := func(i int) Vec {
+		if closed {
+			return points[int(Mod(float64(i), float64(n)))]
+		}
+		// Repeating the ends three times makes the Path reach them.
+		return points[int(Clamp(float64(i-2), 0, float64(n-1)))]
+	}
+
+	segments := n + 1
+	if closed {
+		segments = n
+	}
+	curves := make([]CubicBezier, segments)
+	for i := range curves {
+		c0, c1, c2, c3 := at(i), at(i+1), at(i+2), at(i+3)
+		curves[i] = CubicBezier{
+			P0: c0.Plus(c1.Times(4)).Plus(c2).DividedBy(6),
+			P1: c1.Times(2).Plus(c2).DividedBy(3),
+			P2: c1.Plus(c2.Times(2)).DividedBy(3),
+			P3: c1.Plus(c2.Times(4)).Plus(c3).DividedBy(6),
+		}
+	}
+	return NewPath(closed, curves...)
+}
+
+// newPointPath returns a Path for less than 2 points, which stays at the point if there is
+// one.
+func newPointPath(points []Vec, closed bool) *Path {
+	if len(points) == 0 {
+		return NewPath(closed)
+	}
+	p := points[0]
+	return NewPath(closed, CubicBezier{p, p, p, p})
+}
+
+// Beziers returns the CubicBeziers that make up the Path.
+func (p *Path) Beziers() []CubicBezier {
+	return append([]CubicBezier(nil), p.curves...)
+}
+
+// Closed returns true if the Path is a loop.
+func (p *Path) Closed() bool {
+	return p.closed
+}
+
+// Len returns the length of the Path.
+func (p *Path) Len() float64 {
+	if len(p.ends) == 0 {
+		return 0
+	}
+	return p.ends[len(p.ends)-1]
+}
+
+// curveAt returns the index of the curve at t and the t value within that curve.
+func (p *Path) curveAt(t float64) (int, float64) {
+	n := len(p.curves)
+	if p.closed {
+		t = Mod(t, 1)
+	} else {
+		t = Clamp(t, 0, 1)
+	}
+	t *= float64(n)
+	i := int(t)
+	if i > n-1 {
+		i = n - 1
+	}
+	return i, t - float64(i)
+}
+
+// At returns the point on the Path at t, or the zero Vec if the Path is empty.
+func (p *Path) At(t float64) Vec {
+	if len(p.curves) == 0 {
+		return Vec{}
+	}
+	i, ct := p.curveAt(t)
+	return p.curves[i].At(ct)
+}
+
+// Tangent returns the unit vector in the direction of the Path at t, or the zero Vec if the
+// Path has no direction there.
+func (p *Path) Tangent(t float64) Vec {
+	if len(p.curves) == 0 {
+		return Vec{}
+	}
+	i, ct := p.curveAt(t)
+	return p.curves[i].Tangent(ct)
+}
+
+// TAtLen returns the t value of the point that is dist along the Path from the start. dist
+// wraps around if the Path is closed, otherwise it is clamped to the length of the Path.
+func (p *Path) TAtLen(dist float64) float64 {
+	n := len(p.curves)
+	total := p.Len()
+	if n == 0 || total <= 0 {
+		return 0
+	}
+	if p.closed {
+		dist = Mod(dist, total)
+	} else {
+		dist = Clamp(dist, 0, total)
+	}
+	i := sort.SearchFloat64s(p.ends, dist)
+	if i > n-1 {
+		i = n - 1
+	}
+	start := 0.0
+	if i > 0 {
+		start = p.ends[i-1]
+	}
+	return (float64(i) + p.curves[i].TAtLen(dist-start)) / float64(n)
+}
+
+// AtLen returns the point that is dist along the Path from the start, which moves along the
+// Path at a constant speed as dist changes.
+func (p *Path) AtLen(dist float64) Vec {
+	return p.At(p.TAtLen(dist))
+}
+
+// TangentAtLen returns the unit vector in the direction of the Path at the point that is
+// dist along it from the start.
+func (p *Path) TangentAtLen(dist float64) Vec {
+	return p.Tangent(p.TAtLen(dist))
+}
@@ -0,0 +1,161 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+var splinePoints = []Vec{VecXY(0, 0), VecXY(4, 1), VecXY(5, 6), VecXY(1, 7), VecXY(-3, 3)}
+
+func TestCatmullRom(t *testing.T) {
+	for _, typ := range []CatmullRomType{CatmullRomUniform, CatmullRomCentripetal, CatmullRomChordal} {
+		for _, closed := range []bool{false, true} {
+			p := NewCatmullRom(splinePoints, typ, closed)
+			n := len(splinePoints) - 1
+			if closed {
+				n++
+			}
+			if got := len(p.Beziers()); got != n {
+				t.Errorf("%d %t: got %d curves, want %d", typ, closed, got, n)
+			}
+			// Passes through every point.
+			for i, v := range splinePoints {
+				if got := p.At(float64(i) / float64(n)); !got.Equals(v, e) {
+					t.Errorf("%d %t: point %d: got %s, want %s", typ, closed, i, got, v)
+				}
+			}
+			// The direction doesn't change where curves join.
+			curves := p.Beziers()
+			for i := 1; i < len(curves); i++ {
+				if got, want := curves[i].Tangent(0), curves[i-1].Tangent(1); !got.Equals(want, 1e-9) {
+					t.Errorf("%d %t: joint %d: got %s, want %s", typ, closed, i, got, want)
+				}
+			}
+			if closed {
+				if got, want := curves[0].Tangent(0), curves[n-1].Tangent(1); !got.Equals(want, 1e-9) {
+					t.Errorf("%d %t: loop joint: got %s, want %s", typ, closed, got, want)
+				}
+			}
+		}
+	}
+
+	// Uniform Catmull-Rom matches the usual matrix form.
+	p := NewCatmullRom(splinePoints, CatmullRomUniform, false)
+	p0, p1, p2, p3 := splinePoints[0], splinePoints[1], splinePoints[2], splinePoints[3]
+	for x := 0.0; x <= 1; x += 0.125 {
+		x2, x3 := x*x, x*x*x
+		want := p0.Times(-x3 + 2*x2 - x).
+			Plus(p1.Times(3*x3 - 5*x2 + 2)).
+			Plus(p2.Times(-3*x3 + 4*x2 + x)).
+			Plus(p3.Times(x3 - x2)).
+			Times(0.5)
+		if got := p.At((1 + x) / 4); !got.Equals(want, e) {
+			t.Errorf("uniform %f: got %s, want %s", x, got, want)
+		}
+	}
+
+	// Repeated points don't break the spline.
+	dup := NewCatmullRom([]Vec{VecXY(0, 0), VecXY(0, 0), VecXY(3, 4), VecXY(3, 4), VecXY(6, 0)}, CatmullRomCentripetal, false)
+	for x := 0.0; x <= 1; x += 0.01 {
+		if got := dup.At(x); math.IsNaN(got.X) || math.IsNaN(got.Y) {
+			t.Errorf("duplicates %f: got %s", x, got)
+		}
+	}
+}
+
+func TestBSpline(t *testing.T) {
+	for _, closed := range []bool{false, true} {
+		p := NewBSpline(splinePoints, closed)
+		curves := p.Beziers()
+		// The curves join smoothly, with the same velocity.
+		for i := 1; i < len(curves); i++ {
+			if got, want := curves[i].At(0), curves[i-1].At(1); !got.Equals(want, e) {
+				t.Errorf("%t: joint %d: got %s, want %s", closed, i, got, want)
+			}
+			if got, want := curves[i].Derivative(0), curves[i-1].Derivative(1); !got.Equals(want, e) {
+				t.Errorf("%t: joint %d: derivative got %s, want %s", closed, i, got, want)
+			}
+		}
+		if closed {
+			if got, want := p.At(0), p.At(1); !got.Equals(want, e) {
+				t.Errorf("loop: got %s, want %s", got, want)
+			}
+		} else if p.At(0) != splinePoints[0] || !p.At(1).Equals(splinePoints[len(splinePoints)-1], e) {
+			t.Errorf("ends: got %s and %s", p.At(0), p.At(1))
+		}
+	}
+
+	// A closed B-spline matches the usual basis functions.
+	p := NewBSpline(splinePoints, true)
+	n := float64(len(splinePoints))
+	p0, p1, p2, p3 := splinePoints[0], splinePoints[1], splinePoints[2], splinePoints[3]
+	for x := 0.0; x <= 1; x += 0.125 {
+		u := 1 - x
+		want := p0.Times(u * u * u).
+			Plus(p1.Times(3*x*x*x - 6*x*x + 4)).
+			Plus(p2.Times(-3*x*x*x + 3*x*x + 3*x + 1)).
+			Plus(p3.Times(x * x * x)).
+			DividedBy(6)
+		if got := p.At(x / n); !got.Equals(want, e) {
+			t.Errorf("basis %f: got %s, want %s", x, got, want)
+		}
+	}
+}
+
+func TestPathLen(t *testing.T) {
+	for _, closed := range []bool{false, true} {
+		p := NewCatmullRom(splinePoints, CatmullRomCentripetal, closed)
+		total := 0.0
+		for _, c := range p.Beziers() {
+			total += c.Len()
+		}
+		if got := p.Len(); math.Abs(got-total) > e {
+			t.Errorf("%t: len got %f, want %f", closed, got, total)
+		}
+
+		// Equal steps in distance give points an equal distance apart along the Path.
+		const steps = 50
+		step := total / steps
+		prev := p.AtLen(0)
+		for i := 1; i <= steps; i++ {
+			cur := p.AtLen(float64(i) * step)
+			// Over a short step the straight line is close to the distance along the curve.
+			if d := cur.Dist(prev); d > step+e || d < step*0.9 {
+				t.Errorf("%t: step %d: got %f, want about %f", closed, i, d, step)
+			}
+			prev = cur
+		}
+
+		if closed {
+			if got, want := p.AtLen(total+3), p.AtLen(3); !got.Equals(want, 1e-8) {
+				t.Errorf("wrap: got %s, want %s", got, want)
+			}
+			if got, want := p.AtLen(-3), p.AtLen(total-3); !got.Equals(want, 1e-8) {
+				t.Errorf("wrap back: got %s, want %s", got, want)
+			}
+		} else {
+			if got, want := p.AtLen(total+3), splinePoints[len(splinePoints)-1]; !got.Equals(want, e) {
+				t.Errorf("clamp: got %s, want %s", got, want)
+			}
+			if got, want := p.AtLen(-3), splinePoints[0]; !got.Equals(want, e) {
+				t.Errorf("clamp back: got %s, want %s", got, want)
+			}
+		}
+
+		dist := total * 0.3
+		tAt := p.TAtLen(dist)
+		if got, want := p.TangentAtLen(dist), p.Tangent(tAt); !got.Equals(want, e) {
+			t.Errorf("%t: tangent got %s, want %s", closed, got, want)
+		}
+	}
+
+	// Fewer than 2 points.
+	empty := NewCatmullRom(nil, CatmullRomUniform, false)
+	if empty.Len() != 0 || empty.At(0.5) != (Vec{}) || empty.AtLen(1) != (Vec{}) {
+		t.Errorf("empty: got %f, %s", empty.Len(), empty.At(0.5))
+	}
+	single := NewBSpline([]Vec{VecXY(2, 3)}, true)
+	if single.Len() != 0 || single.AtLen(5) != VecXY(2, 3) || single.Tangent(0.5) != (Vec{}) {
+		t.Errorf("single: got %f, %s, %s", single.Len(), single.AtLen(5), single.Tangent(0.5))
+	}
+}
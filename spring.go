@@ -0,0 +1,118 @@
+package geo
+
+import (
+	"math"
+	"time"
+)
+
+// SpringParams are the physical properties of a damped spring. Its methods are shared by
+// Spring and SpringVec.
+type SpringParams struct {
+	// Stiffness is how strongly the spring pulls towards its target.
+	Stiffness float64
+	// Damping slows the spring down. A Damping of 2*sqrt(Stiffness*Mass) is critically damped,
+	// which reaches the target as quickly as possible without overshooting. Less than that
+	// and the spring will bounce, more than that and it approaches the target more slowly.
+	Damping float64
+	// Mass makes the spring slower to respond. A Mass of 0 or less is treated as 1.
+	Mass float64
+}
+
+// halfLifeOmega is the value of w*t where a critically damped spring starting at rest has
+// covered half the distance to its target, i.e. the solution of exp(-x)*(1+x) = 0.5.
+const halfLifeOmega = 1.6783469900166608
+
+// SpringHalfLife returns the SpringParams of a critically damped spring that covers half of
+// the distance to a still target, starting at rest, in halfLife, like SmoothDamp in Unity.
+func SpringHalfLife(halfLife time.Duration) SpringParams {
+	w := halfLifeOmega / halfLife.Seconds()
+	return SpringParams{Stiffness: w * w, Damping: 2 * w, Mass: 1}
+}
+
+// step returns the offset from the target x and velocity v after dt seconds. The spring is
+// solved exactly so it is stable for any dt.
+func (p SpringParams) step(x, v, dt float64) (float64, float64) {
+	m := p.Mass
+	if m <= 0 {
+		m = 1
+	}
+	k, c := p.Stiffness/m, p.Damping/m
+	if k <= 0 {
+		// Without a spring only damping applies.
+		if c <= 0 {
+			return x + v*dt, v
+		}
+		decay := math.Exp(-c * dt)
+		return x + v/c*(1-decay), v * decay
+	}
+
+	w := math.Sqrt(k)
+	zeta := c / (2 * w)
+	switch {
+	case math.Abs(zeta-1) < 1e-6:
+		// Critically damped.
+		b := v + w*x
+		decay := math.Exp(-w * dt)
+		return decay * (x + b*dt), decay * (v - w*b*dt)
+	case zeta < 1:
+		// Underdamped, so it oscillates.
+		wd := w * math.Sqrt(1-zeta*zeta)
+		a := zeta * w
+		b := (v + a*x) / wd
+		decay := math.Exp(-a * dt)
+		cos, sin := math.Cos(wd*dt), math.Sin(wd*dt)
+		newX := decay * (x*cos + b*sin)
+		newV := decay * ((b*wd-a*x)*cos - (x*wd+a*b)*sin)
+		return newX, newV
+	}
+	// Overdamped.
+	root := w * math.Sqrt(zeta*zeta-1)
+	r1, r2 := -zeta*w+root, -zeta*w-root
+	c2 := (v - r1*x) / (r2 - r1)
+	c1 := x - c2
+	e1, e2 := math.Exp(r1*dt), math.Exp(r2*dt)
+	return c1*e1 + c2*e2, r1*c1*e1 + r2*c2*e2
+}
+
+// Spring moves a float64 Value towards a Target that may change at any time, such as for a
+// camera following a player.
+type Spring struct {
+	Value, Velocity, Target float64
+	SpringParams
+}
+
+// Update moves the Spring forward by dt and returns the new Value.
+func (s *Spring) Update(dt time.Duration) float64 {
+	x, v := s.step(s.Value-s.Target, s.Velocity, dt.Seconds())
+	s.Value, s.Velocity = s.Target+x, v
+	return s.Value
+}
+
+// SpringVec moves a Vec Value towards a Target that may change at any time, such as for a
+// camera following a player.
+type SpringVec struct {
+	Value, Velocity, Target Vec
+	SpringParams
+}
+
+// Update moves the SpringVec forward by dt and returns the new Value.
+func (s *SpringVec) Update(dt time.Duration) Vec {
+	x, vx := s.step(s.Value.X-s.Target.X, s.Velocity.X, dt.Seconds())
+	y, vy := s.step(s.Value.Y-s.Target.Y, s.Velocity.Y, dt.Seconds())
+	s.Value = Vec{X: s.Target.X + x, Y: s.Target.Y + y}
+	s.Velocity = Vec{X: vx, Y: vy}
+	return s.Value
+}
+
+// EaseSpring creates an EaseFn that follows a spring, starting at rest, as it moves from 0 to
+// 1. damping is the damping ratio, where 1 is critically damped and less than 1 bounces.
+// frequency is the number of times the undamped spring would oscillate while t goes from 0 to
+// 1. The result is only exactly 1 at t = 1 if the spring has settled by then.
+func EaseSpring(damping, frequency float64) EaseFn {
+	w := 2 * math.Pi * frequency
+	p := SpringParams{Stiffness: w * w, Damping: 2 * damping * w, Mass: 1}
+	return func(t float64) float64 {
+		x, _ := p.step(-1, 0, t)
+		return 1 + x
+	}
+}
@@ -0,0 +1,114 @@
+package geo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSpringUpdate(t *testing.T) {
+	cases := []SpringParams{
+		{Stiffness: 100, Damping: 5, Mass: 1},
+		{Stiffness: 100, Damping: 20, Mass: 1},
+		{Stiffness: 100, Damping: 50, Mass: 2},
+		{Stiffness: 100, Damping: 60, Mass: 0},
+		{Stiffness: 0, Damping: 3, Mass: 1},
+		{Stiffness: 0, Damping: 0, Mass: 1},
+	}
+
+	for i, c := range cases {
+		// Compare against small steps of semi-implicit Euler integration.
+		x, v := 4.0, -3.0
+		m := c.Mass
+		if m <= 0 {
+			m = 1
+		}
+		const h = 1e-5
+		for step := 0; step < 100000; step++ {
+			a := (-c.Stiffness*x - c.Damping*v) / m
+			v += a * h
+			x += v * h
+		}
+
+		s := Spring{Value: 7, Velocity: -3, Target: 3, SpringParams: c}
+		got := s.Update(time.Second)
+		if math.Abs(got-(3+x)) > 1e-3 || math.Abs(s.Velocity-v) > 1e-3 {
+			t.Errorf("case %d: got %f, %f, want %f, %f", i, got, s.Velocity, 3+x, v)
+		}
+
+		// Many small updates end up at the same place as one big one.
+		s2 := Spring{Value: 7, Velocity: -3, Target: 3, SpringParams: c}
+		for step := 0; step < 10; step++ {
+			s2.Update(time.Second / 10)
+		}
+		if math.Abs(s2.Value-s.Value) > 1e-9 || math.Abs(s2.Velocity-s.Velocity) > 1e-9 {
+			t.Errorf("case %d: small steps: got %f, %f, want %f, %f", i, s2.Value, s2.Velocity, s.Value, s.Velocity)
+		}
+	}
+}
+
+func TestSpringHalfLife(t *testing.T) {
+	s := Spring{Target: 10, SpringParams: SpringHalfLife(time.Second / 4)}
+	if got := s.Update(time.Second / 4); math.Abs(got-5) > e {
+		t.Errorf("half life: got %f, want %f", got, 5.0)
+	}
+	// Critically damped never overshoots.
+	for i := 0; i < 100; i++ {
+		if got := s.Update(time.Second / 20); got > 10 {
+			t.Errorf("overshot: got %f", got)
+		}
+	}
+	if math.Abs(s.Value-10) > 1e-3 {
+		t.Errorf("settled: got %f, want %f", s.Value, 10.0)
+	}
+
+	// Moving the target mid way keeps the motion smooth.
+	s.Target = 0
+	before := s.Velocity
+	s.Update(time.Millisecond)
+	if math.Abs(s.Velocity-before) > 0.5 {
+		t.Errorf("velocity jumped from %f to %f", before, s.Velocity)
+	}
+}
+
+func TestSpringVec(t *testing.T) {
+	p := SpringParams{Stiffness: 50, Damping: 4, Mass: 1.5}
+	sv := SpringVec{Value: VecXY(1, 2), Velocity: VecXY(3, -4), Target: VecXY(-5, 6), SpringParams: p}
+	sx := Spring{Value: 1, Velocity: 3, Target: -5, SpringParams: p}
+	sy := Spring{Value: 2, Velocity: -4, Target: 6, SpringParams: p}
+	for i := 0; i < 10; i++ {
+		got := sv.Update(time.Second / 7)
+		want := VecXY(sx.Update(time.Second/7), sy.Update(time.Second/7))
+		if !got.Equals(want, e) || !sv.Velocity.Equals(VecXY(sx.Velocity, sy.Velocity), e) {
+			t.Errorf("step %d: got %s, %s, want %s, %s", i, got, sv.Velocity, want, VecXY(sx.Velocity, sy.Velocity))
+		}
+	}
+}
+
+func TestEaseSpring(t *testing.T) {
+	cases := []struct {
+		damping, frequency float64
+		overshoot          bool
+	}{
+		{1, 2, false},
+		{0.3, 3, true},
+		{2, 4, false},
+	}
+
+	for i, c := range cases {
+		fn := EaseSpring(c.damping, c.frequency)
+		if got := fn(0); math.Abs(got) > e {
+			t.Errorf("case %d: start: got %f, want 0", i, got)
+		}
+		if got := fn(1); math.Abs(got-1) > 0.05 {
+			t.Errorf("case %d: end: got %f, want about 1", i, got)
+		}
+		max := 0.0
+		for x := 0.0; x <= 1; x += 0.01 {
+			max = math.Max(max, fn(x))
+		}
+		if overshoot := max > 1+e; overshoot != c.overshoot {
+			t.Errorf("case %d: overshoot: got %t (max %f), want %t", i, overshoot, max, c.overshoot)
+		}
+	}
+}
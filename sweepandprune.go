@@ -0,0 +1,158 @@
+package geo
+
+// Axis is one of the two coordinate axes.
+type Axis int
+
+// The axes that a SweepAndPrune can sort along.
+const (
+	AxisX Axis = iota
+	AxisY
+)
+
+// SweepAndPrune is a broadphase for Rects, each identified by an index chosen by the caller,
+// that keeps them sorted by their start along one axis. It works best when most shapes move
+// along the other axis, or only a little between frames, so that the list stays nearly
+// sorted. Changes to the Rects take effect on the next call to Update.
+type SweepAndPrune struct {
+	axis   Axis
+	sorted []*sapItem
+	items  map[int]*sapItem
+	pairs  map[Pair]bool
+}
+
+type sapItem struct {
+	i    int
+	rect Rect
+}
+
+// NewSweepAndPrune creates an empty SweepAndPrune that sorts along the given axis.
+func NewSweepAndPrune(axis Axis) *SweepAndPrune {
+	return &SweepAndPrune{
+		axis:  axis,
+		items: make(map[int]*sapItem),
+		pairs: make(map[Pair]bool),
+	}
+}
+
+// Len returns the number of Rects in the SweepAndPrune.
+func (s *SweepAndPrune) Len() int {
+	return len(s.items)
+}
+
+// InsertRect adds the Rect to the SweepAndPrune with the index i. If i is already in the
+// SweepAndPrune then it is moved instead.
+func (s *SweepAndPrune) InsertRect(i int, r Rect) {
+	s.MoveRect(i, r)
+}
+
+// MoveRect changes the Rect with index i. If i isn't in the SweepAndPrune then it is
+// inserted.
+func (s *SweepAndPrune) MoveRect(i int, r Rect) {
+	item, ok := s.items[i]
+	if !ok {
+		item = &sapItem{i: i}
+		s.items[i] = item
+		s.sorted = append(s.sorted, item)
+	}
+	item.rect = r.Normalized()
+}
+
+// Remove removes the Rect with index i and returns true, or returns false if i isn't in the
+// SweepAndPrune. Any pairs it was part of end on the next call to Update.
+func (s *SweepAndPrune) Remove(i int) bool {
+	item, ok := s.items[i]
+	if !ok {
+		return false
+	}
+	delete(s.items, i)
+	for j, other := range s.sorted {
+		if other == item {
+			copy(s.sorted[j:], s.sorted[j+1:])
+			s.sorted[len(s.sorted)-1] = nil
+			s.sorted = s.sorted[:len(s.sorted)-1]
+			break
+		}
+	}
+	return true
+}
+
+// Update re-sorts the Rects and finds every pair that collides. It returns the pairs that
+// started colliding and the pairs that stopped colliding since the last call to Update, both
+// sorted by A and then B.
+func (s *SweepAndPrune) Update() (begin, end []Pair) {
+	s.sort()
+
+	pairs := make(map[Pair]bool, len(s.pairs))
+	for j, a := range s.sorted {
+		aMax := s.max(a.rect)
+		for _, b := range s.sorted[j+1:] {
+			// Everything after b starts even further along, so none of it can overlap a.
+			if s.min(b.rect) >= aMax {
+				break
+			}
+			if a.rect.CollideRect(b.rect) {
+				if a.i < b.i {
+					pairs[Pair{A: a.i, B: b.i}] = true
+				} else {
+					pairs[Pair{A: b.i, B: a.i}] = true
+				}
+			}
+		}
+	}
+
+	begin = make([]Pair, 0)
+	for p := range pairs {
+		if !s.pairs[p] {
+			begin = append(begin, p)
+		}
+	}
+	end = make([]Pair, 0)
+	for p := range s.pairs {
+		if !pairs[p] {
+			end = append(end, p)
+		}
+	}
+	sortPairs(begin)
+	sortPairs(end)
+	s.pairs = pairs
+	return begin, end
+}
+
+// Pairs returns every pair of Rects that collided as of the last call to Update, each pair
+// exactly once. The pairs are sorted by A and then B.
+func (s *SweepAndPrune) Pairs() []Pair {
+	pairs := make([]Pair, 0, len(s.pairs))
+	for p := range s.pairs {
+		pairs = append(pairs, p)
+	}
+	sortPairs(pairs)
+	return pairs
+}
+
+// sort does an insertion sort of the Rects by their start along the axis, which is fast
+// when they are already nearly sorted from the previous frame.
+func (s *SweepAndPrune) sort() {
+	for j := 1; j < len(s.sorted); j++ {
+		item := s.sorted[j]
+		min := s.min(item.rect)
+		k := j - 1
+		for ; k >= 0 && s.min(s.sorted[k].rect) > min; k-- {
+			s.sorted[k+1] = s.sorted[k]
+		}
+		s.sorted[k+1] = item
+	}
+}
+
+func (s *SweepAndPrune) min(r Rect) float64 {
+	if s.axis == AxisY {
+		return r.Top()
+	}
+	return r.Left()
+}
+
+func (s *SweepAndPrune) max(r Rect) float64 {
+	if s.axis == AxisY {
+		return r.Bottom()
+	}
+	return r.Right()
+}
@@ -0,0 +1,158 @@
+package geo
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSweepAndPruneRandom(t *testing.T) {
+	for _, axis := range []Axis{AxisX, AxisY} {
+		bounds := RectXYWH(-100, -100, 200, 200)
+		s := NewSweepAndPrune(axis)
+		rects := make([]Rect, 200)
+		for i := range rects {
+			rects[i] = randRect(bounds, 15)
+			s.InsertRect(i, rects[i])
+		}
+		if s.Len() != len(rects) {
+			t.Errorf("axis %d: len: got %d, want %d", axis, s.Len(), len(rects))
+		}
+
+		prev := map[Pair]bool{}
+		check := func(stage string) {
+			begin, end := s.Update()
+			want := bruteRectPairs(rects)
+			if got := s.Pairs(); !pairListEqual(got, want) {
+				t.Errorf("axis %d: %s: pairs: got %v, want %v", axis, stage, got, want)
+			}
+
+			cur := map[Pair]bool{}
+			wantBegin := []Pair{}
+			for _, p := range want {
+				cur[p] = true
+				if !prev[p] {
+					wantBegin = append(wantBegin, p)
+				}
+			}
+			wantEnd := []Pair{}
+			for _, p := range sortedPairSet(prev) {
+				if !cur[p] {
+					wantEnd = append(wantEnd, p)
+				}
+			}
+			if !pairListEqual(begin, wantBegin) {
+				t.Errorf("axis %d: %s: begin: got %v, want %v", axis, stage, begin, wantBegin)
+			}
+			if !pairListEqual(end, wantEnd) {
+				t.Errorf("axis %d: %s: end: got %v, want %v", axis, stage, end, wantEnd)
+			}
+			prev = cur
+
+			for j := 1; j < len(s.sorted); j++ {
+				if s.min(s.sorted[j-1].rect) > s.min(s.sorted[j].rect) {
+					t.Errorf("axis %d: %s: not sorted at %d", axis, stage, j)
+				}
+			}
+		}
+		check("inserted")
+
+		for frame := 0; frame < 5; frame++ {
+			for i := range rects {
+				rects[i].Move(rand.Float64()*6-3, rand.Float64()*6-3)
+				s.MoveRect(i, rects[i])
+			}
+			check("moved")
+		}
+
+		// Removed Rects are replaced with ones that never collide, keeping the indices the same.
+		for i := range rects {
+			if i%3 != 0 {
+				if !s.Remove(i) {
+					t.Errorf("axis %d: remove %d: got false, want true", axis, i)
+				}
+				rects[i] = Rect{X: 1000, Y: 1000}
+			}
+		}
+		if s.Remove(1) {
+			t.Errorf("axis %d: remove twice: got true, want false", axis)
+		}
+		if s.Len() != len(s.sorted) {
+			t.Errorf("axis %d: removed: got len %d, sorted %d", axis, s.Len(), len(s.sorted))
+		}
+		check("removed")
+	}
+}
+
+// sortedPairSet returns the pairs in the set, sorted by A and then B.
+func sortedPairSet(set map[Pair]bool) []Pair {
+	pairs := []Pair{}
+	for p := range set {
+		pairs = append(pairs, p)
+	}
+	sortPairs(pairs)
+	return pairs
+}
+
+func TestSweepAndPruneEvents(t *testing.T) {
+	s := NewSweepAndPrune(AxisX)
+	s.InsertRect(2, RectXYWH(0, 0, 10, 10))
+	s.InsertRect(0, RectXYWH(5, 5, 10, 10))
+	s.InsertRect(1, RectXYWH(30, 0, 10, 10))
+	// Overlaps along the sorted axis but not the other one.
+	s.InsertRect(3, RectXYWH(8, 50, -5, 10))
+
+	steps := []struct {
+		move       func()
+		begin, end []Pair
+		pairs      []Pair
+	}{
+		{
+			func() {},
+			[]Pair{{A: 0, B: 2}}, []Pair{},
+			[]Pair{{A: 0, B: 2}},
+		},
+		{
+			func() {},
+			[]Pair{}, []Pair{},
+			[]Pair{{A: 0, B: 2}},
+		},
+		{
+			// Touching isn't colliding.
+			func() { s.MoveRect(1, RectXYWH(15, 0, 10, 10)) },
+			[]Pair{}, []Pair{},
+			[]Pair{{A: 0, B: 2}},
+		},
+		{
+			func() { s.MoveRect(1, RectXYWH(12, 0, 10, 10)) },
+			[]Pair{{A: 0, B: 1}}, []Pair{},
+			[]Pair{{A: 0, B: 1}, {A: 0, B: 2}},
+		},
+		{
+			func() { s.MoveRect(2, RectXYWH(-20, 0, 10, 10)) },
+			[]Pair{}, []Pair{{A: 0, B: 2}},
+			[]Pair{{A: 0, B: 1}},
+		},
+		{
+			func() {
+				s.Remove(0)
+				s.InsertRect(3, RectXYWH(20, 5, 10, 10))
+			},
+			[]Pair{{A: 1, B: 3}}, []Pair{{A: 0, B: 1}},
+			[]Pair{{A: 1, B: 3}},
+		},
+	}
+
+	for i, step := range steps {
+		step.move()
+		begin, end := s.Update()
+		if !pairListEqual(begin, step.begin) {
+			t.Errorf("step %d: begin: got %v, want %v", i, begin, step.begin)
+		}
+		if !pairListEqual(end, step.end) {
+			t.Errorf("step %d: end: got %v, want %v", i, end, step.end)
+		}
+		if got := s.Pairs(); !pairListEqual(got, step.pairs) {
+			t.Errorf("step %d: pairs: got %v, want %v", i, got, step.pairs)
+		}
+	}
+}
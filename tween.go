@@ -0,0 +1,328 @@
+package geo
+
+import "time"
+
+// Animation is something that plays out over time, like a Tween or TweenGroup.
+type Animation interface {
+	// Update advances the Animation by dt, unless it is paused, and returns true if it is
+	// done.
+	Update(dt time.Duration) bool
+	// Seek moves the Animation to the given time since it started, calling any callbacks
+	// that are passed along the way when moving forward.
+	Seek(elapsed time.Duration)
+	// TotalDuration returns how long the Animation takes, or a negative number if it repeats
+	// forever.
+	TotalDuration() time.Duration
+}
+
+// playhead keeps track of the time of an Animation.
+type playhead struct {
+	elapsed time.Duration
+	paused  bool
+	last    time.Time
+	// completed is true once OnComplete has been called, until seeking back before the end.
+	completed bool
+}
+
+// Elapsed returns the time since the Animation started, including time spent in delays.
+func (p *playhead) Elapsed() time.Duration {
+	return p.elapsed
+}
+
+// Pause stops Update from advancing the Animation.
+func (p *playhead) Pause() {
+	p.paused = true
+}
+
+// Resume undoes Pause.
+func (p *playhead) Resume() {
+	p.paused = false
+}
+
+// Paused returns true if the Animation is paused.
+func (p *playhead) Paused() bool {
+	return p.paused
+}
+
+// complete calls onComplete the first time done is true.
+func (p *playhead) complete(done bool, onComplete func()) {
+	if !done {
+		p.completed = false
+	} else if !p.completed {
+		p.completed = true
+		if onComplete != nil {
+			onComplete()
+		}
+	}
+}
+
+// timeDelta returns the time since the last call, or 0 for the first call.
+func (p *playhead) timeDelta(now time.Time) time.Duration {
+	var dt time.Duration
+	if !p.last.IsZero() {
+		dt = now.Sub(p.last)
+	}
+	p.last = now
+	return dt
+}
+
+// TweenTiming controls how a tween progresses over time. Its methods are shared by Tween and
+// TweenVec. The zero value plays once, linearly, taking no time.
+type TweenTiming struct {
+	// Delay is how long to wait before starting.
+	Delay time.Duration
+	// Duration is how long it takes to play once.
+	Duration time.Duration
+	// Ease is the path to follow. A nil Ease is linear.
+	Ease EaseFn
+	// Repeat is how many more times to play after the first. A negative Repeat repeats
+	// forever.
+	Repeat int
+	// Yoyo makes every other repeat play backwards.
+	Yoyo bool
+	// Reverse plays from the end to the start.
+	Reverse bool
+	// OnRepeat, if not nil, is called each time a repeat begins.
+	OnRepeat func()
+	// OnComplete, if not nil, is called when the tween finishes.
+	OnComplete func()
+
+	playhead
+}
+
+// TotalDuration returns how long the tween takes, including the delay and any repeats, or a
+// negative number if it repeats forever.
+func (t *TweenTiming) TotalDuration() time.Duration {
+	if t.Repeat < 0 {
+		return -1
+	}
+	return t.Delay + time.Duration(t.Repeat+1)*t.Duration
+}
+
+// Done returns true if the tween has finished.
+func (t *TweenTiming) Done() bool {
+	total := t.TotalDuration()
+	return total >= 0 && t.elapsed >= total
+}
+
+// Update advances the tween by dt, unless it is paused, and returns true if it is done.
+func (t *TweenTiming) Update(dt time.Duration) bool {
+	if !t.paused {
+		t.Seek(t.elapsed + dt)
+	}
+	return t.Done()
+}
+
+// UpdateTime is like Update except that it advances by the time since the last call to
+// UpdateTime or Start. Time spent paused is skipped.
+func (t *TweenTiming) UpdateTime(now time.Time) bool {
+	return t.Update(t.timeDelta(now))
+}
+
+// Start restarts the tween and makes now the time that UpdateTime measures from.
+func (t *TweenTiming) Start(now time.Time) {
+	t.Seek(0)
+	t.last = now
+}
+
+// Seek moves the tween to the given time since it started, calling OnRepeat and OnComplete
+// if they are passed along the way. Seek works even while paused.
+func (t *TweenTiming) Seek(elapsed time.Duration) {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if total := t.TotalDuration(); total >= 0 && elapsed > total {
+		elapsed = total
+	}
+	before := t.iterations(t.elapsed)
+	t.elapsed = elapsed
+	if after := t.iterations(elapsed); after > before && t.OnRepeat != nil {
+		// Finishing the last iteration is completion rather than a repeat.
+		if t.Done() {
+			after--
+		}
+		for i := before; i < after; i++ {
+			t.OnRepeat()
+		}
+	}
+	t.complete(t.Done(), t.OnComplete)
+}
+
+// iterations returns the number of times the tween has been played completely by elapsed.
+func (t *TweenTiming) iterations(elapsed time.Duration) int {
+	active := elapsed - t.Delay
+	if active < 0 {
+		return 0
+	}
+	if t.Duration <= 0 {
+		if t.Repeat < 0 {
+			return 0
+		}
+		return t.Repeat + 1
+	}
+	n := int(active / t.Duration)
+	if t.Repeat >= 0 && n > t.Repeat+1 {
+		n = t.Repeat + 1
+	}
+	return n
+}
+
+// Progress returns how far along the current iteration the tween is, following Ease, where
+// 0 is the start and 1 is the end.
+func (t *TweenTiming) Progress() float64 {
+	active := t.elapsed - t.Delay
+	if active < 0 {
+		active = 0
+	}
+	iteration := t.iterations(t.elapsed)
+	x := 0.0
+	if t.Duration > 0 {
+		x = float64(active-time.Duration(iteration)*t.Duration) / float64(t.Duration)
+	}
+	// Once finished, stay at the end of the last iteration.
+	if t.Repeat >= 0 && iteration > t.Repeat {
+		iteration = t.Repeat
+		x = 1
+	}
+	if t.Reverse != (t.Yoyo && iteration%2 == 1) {
+		x = 1 - x
+	}
+	if t.Ease == nil {
+		return x
+	}
+	return t.Ease(x)
+}
+
+// Tween animates a float64 from From to To.
+type Tween struct {
+	From, To float64
+	TweenTiming
+}
+
+// NewTween creates a Tween that goes from from to to over duration following ease.
+func NewTween(from, to float64, duration time.Duration, ease EaseFn) *Tween {
+	return &Tween{From: from, To: to, TweenTiming: TweenTiming{Duration: duration, Ease: ease}}
+}
+
+// Value returns the current value of the Tween.
+func (t *Tween) Value() float64 {
+	return Lerp(t.From, t.To, t.Progress())
+}
+
+// TweenVec animates a Vec from From to To.
+type TweenVec struct {
+	From, To Vec
+	TweenTiming
+}
+
+// NewTweenVec creates a TweenVec that goes from from to to over duration following ease.
+func NewTweenVec(from, to Vec, duration time.Duration, ease EaseFn) *TweenVec {
+	return &TweenVec{From: from, To: to, TweenTiming: TweenTiming{Duration: duration, Ease: ease}}
+}
+
+// Value returns the current value of the TweenVec.
+func (t *TweenVec) Value() Vec {
+	return LerpVec(t.From, t.To, t.Progress())
+}
+
+// TweenGroup plays a number of Animations, either one after another or all at once. Since a
+// TweenGroup is also an Animation, groups can be nested.
+type TweenGroup struct {
+	// OnComplete, if not nil, is called when all of the Animations have finished.
+	OnComplete func()
+
+	anims    []Animation
+	sequence bool
+	playhead
+}
+
+// NewSequence creates a TweenGroup that plays the Animations one after another. Any
+// Animations after one that repeats forever never play.
+func NewSequence(anims ...Animation) *TweenGroup {
+	return &TweenGroup{anims: anims, sequence: true}
+}
+
+// NewParallel creates a TweenGroup that plays the Animations all at once.
+func NewParallel(anims ...Animation) *TweenGroup {
+	return &TweenGroup{anims: anims}
+}
+
+// TotalDuration returns how long it takes for all of the Animations to finish, or a
+// negative number if any of them repeats forever.
+func (g *TweenGroup) TotalDuration() time.Duration {
+	var total time.Duration
+	for _, a := range g.anims {
+		d := a.TotalDuration()
+		if d < 0 {
+			return -1
+		}
+		if g.sequence {
+			total += d
+		} else if d > total {
+			total = d
+		}
+	}
+	return total
+}
+
+// Done returns true if all of the Animations have finished.
+func (g *TweenGroup) Done() bool {
+	total := g.TotalDuration()
+	return total >= 0 && g.elapsed >= total
+}
+
+// Update advances the TweenGroup by dt, unless it is paused, and returns true if it is done.
+func (g *TweenGroup) Update(dt time.Duration) bool {
+	if !g.paused {
+		g.Seek(g.elapsed + dt)
+	}
+	return g.Done()
+}
+
+// UpdateTime is like Update except that it advances by the time since the last call to
+// UpdateTime or Start. Time spent paused is skipped.
+func (g *TweenGroup) UpdateTime(now time.Time) bool {
+	return g.Update(g.timeDelta(now))
+}
+
+// Start restarts the TweenGroup and makes now the time that UpdateTime measures from.
+func (g *TweenGroup) Start(now time.Time) {
+	g.Seek(0)
+	g.last = now
+}
+
+// Seek moves each of the Animations to where they should be at the given time since the
+// TweenGroup started.
+func (g *TweenGroup) Seek(elapsed time.Duration) {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	if total := g.TotalDuration(); total >= 0 && elapsed > total {
+		elapsed = total
+	}
+	g.elapsed = elapsed
+
+	var offset time.Duration
+	for _, a := range g.anims {
+		d := a.TotalDuration()
+		local := elapsed
+		if g.sequence {
+			local -= offset
+			if local < 0 || offset < 0 {
+				local = 0
+			}
+			if d < 0 {
+				// Nothing after an Animation that repeats forever is reached.
+				offset = -1
+			} else if offset >= 0 {
+				offset += d
+			}
+		}
+		if d >= 0 && local > d {
+			local = d
+		}
+		a.Seek(local)
+	}
+
+	g.complete(g.Done(), g.OnComplete)
+}
@@ -0,0 +1,186 @@
+package geo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTweenValue(t *testing.T) {
+	s := time.Second
+	cases := []struct {
+		timing  TweenTiming
+		elapsed time.Duration
+		want    float64
+	}{
+		{TweenTiming{Duration: s}, 0, 10},
+		{TweenTiming{Duration: s}, s / 4, 12.5},
+		{TweenTiming{Duration: s}, s, 20},
+		{TweenTiming{Duration: s}, 2 * s, 20},
+		{TweenTiming{Duration: s, Ease: EaseIn(2)}, s / 2, 12.5},
+		{TweenTiming{Duration: s, Delay: s}, s / 2, 10},
+		{TweenTiming{Duration: s, Delay: s}, s * 3 / 2, 15},
+		{TweenTiming{Duration: s, Reverse: true}, s / 4, 17.5},
+		{TweenTiming{Duration: s, Reverse: true}, s, 10},
+		{TweenTiming{Duration: s, Repeat: 2}, s * 5 / 4, 12.5},
+		{TweenTiming{Duration: s, Repeat: 2}, 3 * s, 20},
+		{TweenTiming{Duration: s, Repeat: 2, Yoyo: true}, s * 5 / 4, 17.5},
+		{TweenTiming{Duration: s, Repeat: 2, Yoyo: true}, s * 9 / 4, 12.5},
+		{TweenTiming{Duration: s, Repeat: 1, Yoyo: true}, 2 * s, 10},
+		{TweenTiming{Duration: s, Repeat: 1, Yoyo: true, Reverse: true}, s * 5 / 4, 12.5},
+		{TweenTiming{Duration: s, Repeat: -1}, s*100 + s/4, 12.5},
+		{TweenTiming{Repeat: 3}, 0, 20},
+	}
+
+	for i, c := range cases {
+		tw := &Tween{From: 10, To: 20, TweenTiming: c.timing}
+		tw.Seek(c.elapsed)
+		if got := tw.Value(); math.Abs(got-c.want) > e {
+			t.Errorf("case %d: got %f, want %f", i, got, c.want)
+		}
+	}
+}
+
+func TestTweenUpdate(t *testing.T) {
+	tw := NewTweenVec(VecXY(0, 0), VecXY(10, 20), 4*time.Second, nil)
+	tw.Repeat = 1
+	repeats, completes := 0, 0
+	tw.OnRepeat = func() { repeats++ }
+	tw.OnComplete = func() { completes++ }
+
+	steps := []struct {
+		dt        time.Duration
+		pause     bool
+		want      Vec
+		done      bool
+		repeats   int
+		completes int
+	}{
+		{time.Second, false, VecXY(2.5, 5), false, 0, 0},
+		{time.Second, true, VecXY(2.5, 5), false, 0, 0},
+		{2 * time.Second, false, VecXY(7.5, 15), false, 0, 0},
+		{2 * time.Second, false, VecXY(2.5, 5), false, 1, 0},
+		{10 * time.Second, false, VecXY(10, 20), true, 1, 1},
+		{time.Second, false, VecXY(10, 20), true, 1, 1},
+	}
+
+	for i, step := range steps {
+		if step.pause {
+			tw.Pause()
+		}
+		done := tw.Update(step.dt)
+		tw.Resume()
+		if got := tw.Value(); !got.Equals(step.want, e) || done != step.done {
+			t.Errorf("step %d: got %s, %t, want %s, %t", i, got, done, step.want, step.done)
+		}
+		if repeats != step.repeats || completes != step.completes {
+			t.Errorf("step %d: got %d repeats, %d completes, want %d, %d",
+				i, repeats, completes, step.repeats, step.completes)
+		}
+	}
+
+	// Seeking back and finishing again calls OnComplete again. Skipping over several repeats
+	// calls OnRepeat for each.
+	tw.Repeat = 3
+	tw.Seek(time.Second)
+	tw.Update(time.Minute)
+	if repeats != 4 || completes != 2 {
+		t.Errorf("replay: got %d repeats, %d completes, want %d, %d", repeats, completes, 4, 2)
+	}
+}
+
+func TestTweenUpdateTime(t *testing.T) {
+	start := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	tw := NewTween(0, 100, 10*time.Second, nil)
+	tw.Start(start)
+	tw.UpdateTime(start.Add(2 * time.Second))
+	if got := tw.Value(); got != 20 {
+		t.Errorf("got %f, want %f", got, 20.0)
+	}
+
+	// Time spent paused doesn't count.
+	tw.Pause()
+	tw.UpdateTime(start.Add(5 * time.Second))
+	tw.Resume()
+	tw.UpdateTime(start.Add(6 * time.Second))
+	if got := tw.Value(); got != 30 {
+		t.Errorf("paused: got %f, want %f", got, 30.0)
+	}
+	if got := tw.Elapsed(); got != 3*time.Second {
+		t.Errorf("elapsed: got %s, want %s", got, 3*time.Second)
+	}
+
+	tw.Start(start.Add(time.Hour))
+	if done := tw.UpdateTime(start.Add(time.Hour + 11*time.Second)); !done || tw.Value() != 100 {
+		t.Errorf("restart: got %f, %t, want %f, %t", tw.Value(), done, 100.0, true)
+	}
+}
+
+func TestTweenGroup(t *testing.T) {
+	s := time.Second
+	a := NewTween(0, 10, 2*s, nil)
+	b := NewTween(0, 10, s, nil)
+	b.Delay = s
+	c := NewTweenVec(VecXY(0, 0), VecXY(10, 10), s, nil)
+	c.Repeat = 1
+	order := []string{}
+	a.OnComplete = func() { order = append(order, "a") }
+	b.OnComplete = func() { order = append(order, "b") }
+	c.OnComplete = func() { order = append(order, "c") }
+
+	par := NewParallel(b, c)
+	par.OnComplete = func() { order = append(order, "par") }
+	seq := NewSequence(a, par)
+	seq.OnComplete = func() { order = append(order, "seq") }
+
+	if got, want := seq.TotalDuration(), 4*s; got != want {
+		t.Errorf("duration: got %s, want %s", got, want)
+	}
+
+	steps := []struct {
+		dt      time.Duration
+		a, b    float64
+		c       Vec
+		done    bool
+		ordered []string
+	}{
+		{s, 5, 0, VecXY(0, 0), false, []string{}},
+		{s * 3 / 2, 10, 0, VecXY(5, 5), false, []string{"a"}},
+		{s, 10, 5, VecXY(5, 5), false, []string{"a"}},
+		{s, 10, 10, VecXY(10, 10), true, []string{"a", "b", "c", "par", "seq"}},
+	}
+
+	for i, step := range steps {
+		done := seq.Update(step.dt)
+		if a.Value() != step.a || b.Value() != step.b || !c.Value().Equals(step.c, e) || done != step.done {
+			t.Errorf("step %d: got %f, %f, %s, %t, want %f, %f, %s, %t",
+				i, a.Value(), b.Value(), c.Value(), done, step.a, step.b, step.c, step.done)
+		}
+		if len(order) != len(step.ordered) {
+			t.Errorf("step %d: got %v, want %v", i, order, step.ordered)
+			continue
+		}
+		for j := range order {
+			if order[j] != step.ordered[j] {
+				t.Errorf("step %d: got %v, want %v", i, order, step.ordered)
+				break
+			}
+		}
+	}
+
+	seq.Seek(s)
+	if a.Value() != 5 || b.Value() != 0 || seq.Done() {
+		t.Errorf("seek back: got %f, %f, %t, want %f, %f, %t", a.Value(), b.Value(), seq.Done(), 5.0, 0.0, false)
+	}
+
+	forever := NewTween(0, 1, s, nil)
+	forever.Repeat = -1
+	after := NewTween(0, 1, s, nil)
+	loop := NewSequence(forever, after)
+	if loop.TotalDuration() >= 0 {
+		t.Errorf("forever: got %s, want negative", loop.TotalDuration())
+	}
+	if loop.Update(time.Hour) || after.Elapsed() != 0 {
+		t.Errorf("forever: got done %t, after elapsed %s", loop.Done(), after.Elapsed())
+	}
+}
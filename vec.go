@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"image"
 	"math"
-	"math/rand"
 )
 
 // Vec is a 2-D vector. Many of the functions for Vec have two versions, one that modifies
@@ -242,7 +241,12 @@ func (v Vec) Rotated(rad float64) Vec {
 
 // RandVec returns a unit vector in a random direction.
 func RandVec() Vec {
-	rad := rand.Float64() * 2 * math.Pi
+	return defaultRand.RandVec()
+}
+
+// RandVec is like the package level RandVec but draws from r.
+func (r *Rand) RandVec() Vec {
+	rad := r.float64() * 2 * math.Pi
 	return Vec{X: math.Cos(rad), Y: math.Sin(rad)}
 }
 
@@ -0,0 +1,158 @@
+package geo
+
+import "math"
+
+// Metric is a way of measuring the distance between points.
+type Metric int
+
+// The distance metrics.
+const (
+	// Euclidean is the straight line distance, which makes round cells.
+	Euclidean Metric = iota
+	// Manhattan is the sum of the distances along each axis, which makes diamond shaped
+	// cells.
+	Manhattan
+	// Chebyshev is the largest of the distances along each axis, which makes square cells.
+	Chebyshev
+)
+
+// dist returns the distance of the vector (dx, dy, dz) using the Metric.
+func (m Metric) dist(dx, dy, dz float64) float64 {
+	switch m {
+	case Manhattan:
+		return math.Abs(dx) + math.Abs(dy) + math.Abs(dz)
+	case Chebyshev:
+		return math.Max(math.Abs(dx), math.Max(math.Abs(dy), math.Abs(dz)))
+	}
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// WorleyFeature is the value that the Worley functions return.
+type WorleyFeature int
+
+// The Worley features.
+const (
+	// WorleyF1 is the distance to the closest point, which looks like a cluster of cells.
+	WorleyF1 WorleyFeature = iota
+	// WorleyF2 is the distance to the second closest point.
+	WorleyF2
+	// WorleyF2F1 is F2 minus F1, which is 0 along the edges between cells so it looks like
+	// cracks or stone walls.
+	WorleyF2F1
+)
+
+// Worley2 implements 2-D Worley, or cellular, noise. Space is divided into a grid of cells
+// with one randomly placed point in each, and the result is based on the distance to the
+// nearest points, using the given metric. Distances are scaled so that the furthest F1 can
+// be is 1, and larger values of F2 are clamped, giving values between 0 and 1.
+func Worley2(x, y float64, feature WorleyFeature, metric Metric) float64 {
+	return defaultNoise.Worley2(x, y, feature, metric)
+}
+
+// Worley2 is like the package level Worley2 but uses n's permutation to place the points.
+func (n *Noise) Worley2(x, y float64, feature WorleyFeature, metric Metric) float64 {
+	xi := int(math.Floor(x))
+	yi := int(math.Floor(y))
+	f1, f2 := math.Inf(1), math.Inf(1)
+	// Search rings of cells around the one containing (x, y) until the rest are too far away
+	// to contain either of the two closest points.
+	for r := 0; f2 > ringDist(r-1, x-float64(xi), y-float64(yi)); r++ {
+		for cx := xi - r; cx <= xi+r; cx++ {
+			for cy := yi - r; cy <= yi+r; cy++ {
+				if !onRing(r, cx-xi, cy-yi, 0) {
+					continue
+				}
+				h := n.p[n.p[cx&255]+cy&255]
+				d := metric.dist(float64(cx)+n.jitter(h, 0)-x, float64(cy)+n.jitter(h, 2)-y, 0)
+				f1, f2 = closest2(f1, f2, d)
+			}
+		}
+	}
+	return worleyValue(f1, f2, feature, metric.dist(1, 1, 0))
+}
+
+// Worley3 implements 3-D Worley noise. See Worley2 for details.
+func Worley3(x, y, z float64, feature WorleyFeature, metric Metric) float64 {
+	return defaultNoise.Worley3(x, y, z, feature, metric)
+}
+
+// Worley3 is like the package level Worley3 but uses n's permutation to place the points.
+func (n *Noise) Worley3(x, y, z float64, feature WorleyFeature, metric Metric) float64 {
+	xi := int(math.Floor(x))
+	yi := int(math.Floor(y))
+	zi := int(math.Floor(z))
+	f1, f2 := math.Inf(1), math.Inf(1)
+	for r := 0; f2 > ringDist(r-1, x-float64(xi), y-float64(yi), z-float64(zi)); r++ {
+		for cx := xi - r; cx <= xi+r; cx++ {
+			for cy := yi - r; cy <= yi+r; cy++ {
+				for cz := zi - r; cz <= zi+r; cz++ {
+					if !onRing(r, cx-xi, cy-yi, cz-zi) {
+						continue
+					}
+					h := n.p[n.p[n.p[cx&255]+cy&255]+cz&255]
+					d := metric.dist(
+						float64(cx)+n.jitter(h, 0)-x,
+						float64(cy)+n.jitter(h, 2)-y,
+						float64(cz)+n.jitter(h, 4)-z)
+					f1, f2 = closest2(f1, f2, d)
+				}
+			}
+		}
+	}
+	return worleyValue(f1, f2, feature, metric.dist(1, 1, 1))
+}
+
+// onRing returns true if the cell offset (dx, dy, dz) is on the surface of the cube of cells
+// with radius r, rather than inside it.
+func onRing(r, dx, dy, dz int) bool {
+	abs := func(i int) int {
+		if i < 0 {
+			return -i
+		}
+		return i
+	}
+	return abs(dx) == r || abs(dy) == r || abs(dz) == r
+}
+
+// ringDist returns the smallest distance, in any Metric, from a point to a cell outside the
+// cube of cells with radius r around the point's cell, or -1 if r is negative. frac is the
+// position of the point within its cell. Every Metric is at least as large as the distance
+// along any one axis, so that is used.
+func ringDist(r int, frac ...float64) float64 {
+	if r < 0 {
+		return -1
+	}
+	d := math.Inf(1)
+	for _, f := range frac {
+		d = math.Min(d, math.Min(float64(r)+f, float64(r)+1-f))
+	}
+	return d
+}
+
+// jitter returns a number in [0, 1) for the cell with hash h. Different values of k give
+// different numbers for the same cell.
+func (n *Noise) jitter(h, k int) float64 {
+	return float64(n.p[h+k]<<8|n.p[h+k+1]) / (1 << 16)
+}
+
+// closest2 returns the two smallest of f1, f2, and d, given that f1 <= f2.
+func closest2(f1, f2, d float64) (float64, float64) {
+	if d < f1 {
+		return d, f1
+	}
+	if d < f2 {
+		return f1, d
+	}
+	return f1, f2
+}
+
+func worleyValue(f1, f2 float64, feature WorleyFeature, maxDist float64) float64 {
+	val := f1
+	switch feature {
+	case WorleyF2:
+		val = f2
+	case WorleyF2F1:
+		val = f2 - f1
+	}
+	return Clamp(val/maxDist, 0, 1)
+}
@@ -0,0 +1,178 @@
+package geo
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestMetricDist(t *testing.T) {
+	cases := []struct {
+		metric     Metric
+		dx, dy, dz float64
+		want       float64
+	}{
+		{Euclidean, 3, -4, 0, 5},
+		{Euclidean, 2, 3, -6, 7},
+		{Manhattan, 3, -4, 0, 7},
+		{Manhattan, -1, 2, 3, 6},
+		{Chebyshev, 3, -4, 0, 4},
+		{Chebyshev, -1, 2, -5, 5},
+	}
+
+	for i, c := range cases {
+		got := c.metric.dist(c.dx, c.dy, c.dz)
+		if got != c.want {
+			t.Errorf("case %d: got %f, want %f", i, got, c.want)
+		}
+	}
+}
+
+func TestWorley(t *testing.T) {
+	n := NewNoise(rand.NewSource(5))
+	for _, metric := range []Metric{Euclidean, Manhattan, Chebyshev} {
+		fns := []struct {
+			name string
+			fn   func(v []float64, feature WorleyFeature) float64
+			dims int
+		}{
+			{"2-D", func(v []float64, f WorleyFeature) float64 { return n.Worley2(v[0], v[1], f, metric) }, 2},
+			{"3-D", func(v []float64, f WorleyFeature) float64 { return n.Worley3(v[0], v[1], v[2], f, metric) }, 3},
+		}
+		for _, fn := range fns {
+			for i := 0; i < 200; i++ {
+				v := []float64{rand.Float64()*600 - 300, rand.Float64()*600 - 300, rand.Float64()*600 - 300}
+				f1, f2, f2f1 := fn.fn(v, WorleyF1), fn.fn(v, WorleyF2), fn.fn(v, WorleyF2F1)
+				for _, got := range []float64{f1, f2, f2f1} {
+					if got < 0 || got > 1 {
+						t.Errorf("metric %d: %s: out of range: got %f at %v", metric, fn.name, got, v)
+					}
+				}
+				if f1 > f2 {
+					t.Errorf("metric %d: %s: f1 %f > f2 %f at %v", metric, fn.name, f1, f2, v)
+				}
+				if f2 < 1 && math.Abs(f2f1-(f2-f1)) > e {
+					t.Errorf("metric %d: %s: f2-f1: got %f, want %f at %v", metric, fn.name, f2f1, f2-f1, v)
+				}
+
+				// F1 is continuous.
+				w := append([]float64{}, v...)
+				w[rand.Intn(fn.dims)] += 1e-4
+				if diff := math.Abs(fn.fn(w, WorleyF1) - f1); diff > 1e-3 {
+					t.Errorf("metric %d: %s: discontinuous: %v to %v changed by %f", metric, fn.name, v, w, diff)
+				}
+			}
+		}
+	}
+}
+
+func TestWorleyFeaturePoint(t *testing.T) {
+	n := NewNoise(rand.NewSource(7))
+	for cx := -3; cx < 3; cx++ {
+		for cy := -3; cy < 3; cy++ {
+			h := n.p[n.p[cx&255]+cy&255]
+			x, y := float64(cx)+n.jitter(h, 0), float64(cy)+n.jitter(h, 2)
+			if got := n.Worley2(x, y, WorleyF1, Euclidean); got != 0 {
+				t.Errorf("cell %d, %d: got %f, want 0", cx, cy, got)
+			}
+			if got := n.Worley2(x, y, WorleyF2F1, Euclidean); got != n.Worley2(x, y, WorleyF2, Euclidean) {
+				t.Errorf("cell %d, %d: f2-f1: got %f, want f2", cx, cy, got)
+			}
+		}
+	}
+
+	// The package level functions use a different permutation.
+	differs := false
+	for i := 0; i < 20; i++ {
+		x, y := rand.Float64()*10, rand.Float64()*10
+		if Worley2(x, y, WorleyF1, Euclidean) != n.Worley2(x, y, WorleyF1, Euclidean) {
+			differs = true
+		}
+	}
+	if !differs {
+		t.Errorf("different permutations gave the same noise")
+	}
+}
+
+// bruteWorley returns F1 and F2 at v by checking every cell within radius cells of v's cell.
+func bruteWorley(n *Noise, v []float64, metric Metric, radius int) (f1, f2 float64) {
+	f1, f2 = math.Inf(1), math.Inf(1)
+	xi, yi := int(math.Floor(v[0])), int(math.Floor(v[1]))
+	for cx := xi - radius; cx <= xi+radius; cx++ {
+		for cy := yi - radius; cy <= yi+radius; cy++ {
+			if len(v) == 2 {
+				h := n.p[n.p[cx&255]+cy&255]
+				d := metric.dist(float64(cx)+n.jitter(h, 0)-v[0], float64(cy)+n.jitter(h, 2)-v[1], 0)
+				f1, f2 = closest2(f1, f2, d)
+				continue
+			}
+			zi := int(math.Floor(v[2]))
+			for cz := zi - radius; cz <= zi+radius; cz++ {
+				h := n.p[n.p[n.p[cx&255]+cy&255]+cz&255]
+				d := metric.dist(float64(cx)+n.jitter(h, 0)-v[0], float64(cy)+n.jitter(h, 2)-v[1],
+					float64(cz)+n.jitter(h, 4)-v[2])
+				f1, f2 = closest2(f1, f2, d)
+			}
+		}
+	}
+	return
+}
+
+func TestWorleyBruteForce(t *testing.T) {
+	n := NewNoise(rand.NewSource(3))
+	r := rand.New(rand.NewSource(4))
+	for _, metric := range []Metric{Euclidean, Manhattan, Chebyshev} {
+		for i := 0; i < 20000; i++ {
+			v := []float64{r.Float64()*600 - 300, r.Float64()*600 - 300, r.Float64()*600 - 300}
+			dims := 2 + i%2
+			v = v[:dims]
+			f1, f2 := bruteWorley(n, v, metric, 3)
+			maxDist := metric.dist(1, 1, 0)
+			got := func(f WorleyFeature) float64 { return n.Worley2(v[0], v[1], f, metric) }
+			if dims == 3 {
+				maxDist = metric.dist(1, 1, 1)
+				got = func(f WorleyFeature) float64 { return n.Worley3(v[0], v[1], v[2], f, metric) }
+			}
+			for _, c := range []struct {
+				feature WorleyFeature
+				want    float64
+			}{
+				{WorleyF1, f1},
+				{WorleyF2, f2},
+				{WorleyF2F1, f2 - f1},
+			} {
+				want := Clamp(c.want/maxDist, 0, 1)
+				if g := got(c.feature); math.Abs(g-want) > e {
+					t.Errorf("metric %d: feature %d: got %f, want %f at %v", metric, c.feature, g, want, v)
+				}
+			}
+		}
+	}
+}
+
+func TestWorleyContinuous(t *testing.T) {
+	n := NewNoise(rand.NewSource(6))
+	r := rand.New(rand.NewSource(8))
+	const step = 1e-3
+	for _, metric := range []Metric{Euclidean, Manhattan, Chebyshev} {
+		for _, feature := range []WorleyFeature{WorleyF1, WorleyF2, WorleyF2F1} {
+			// Moving by step along an axis changes each distance by at most step, so F2-F1 by
+			// at most twice that.
+			limit := step / metric.dist(1, 1, 0)
+			if feature == WorleyF2F1 {
+				limit *= 2
+			}
+			x, y := r.Float64()*100, r.Float64()*100
+			prev := n.Worley2(x, y, feature, metric)
+			for i := 0; i < 20000; i++ {
+				x += step
+				cur := n.Worley2(x, y, feature, metric)
+				if diff := math.Abs(cur - prev); diff > limit+e {
+					t.Errorf("metric %d: feature %d: changed by %f at (%f, %f)", metric, feature, diff, x, y)
+					break
+				}
+				prev = cur
+			}
+		}
+	}
+}